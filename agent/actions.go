@@ -0,0 +1,41 @@
+package agent
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// parseActionAllowlist parses an environment variable containing a comma-separated
+// list of name:actions entries, where actions are separated by "|" and "*" allows
+// all actions (e.g. "nginx:start|restart,php-fpm:*"). Invalid entries are skipped.
+func parseActionAllowlist(envName string, validActions []string) map[string][]string {
+	allowlist := make(map[string][]string)
+	value, _ := GetEnv(envName)
+	for entry := range strings.SplitSeq(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, actionList, ok := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			slog.Warn("Invalid "+envName+" entry", "entry", entry)
+			continue
+		}
+		for action := range strings.SplitSeq(actionList, "|") {
+			action = strings.ToLower(strings.TrimSpace(action))
+			if action == "" {
+				continue
+			}
+			if action != "*" && !slices.Contains(validActions, action) {
+				slog.Warn("Invalid "+envName+" action", "name", name, "action", action)
+				continue
+			}
+			if !slices.Contains(allowlist[name], action) {
+				allowlist[name] = append(allowlist[name], action)
+			}
+		}
+	}
+	return allowlist
+}
@@ -49,6 +49,7 @@ type Agent struct {
 	keys                      []gossh.PublicKey                                     // SSH public keys
 	smartManager              *SmartManager                                         // Manages SMART data
 	systemdManager            *systemdManager                                       // Manages systemd services
+	auditLog                  *auditLog                                             // Local record of actions requested by the hub
 }
 
 // NewAgent creates a new agent with the given data directory for persisting data.
@@ -71,6 +72,7 @@ func NewAgent(dataDir ...string) (agent *Agent, err error) {
 	} else {
 		slog.Info("Data directory", "path", agent.dataDir)
 	}
+	agent.auditLog = newAuditLog(agent.dataDir)
 
 	agent.memCalc, _ = GetEnv("MEM_CALC")
 	agent.sensorConfig = agent.newSensorConfig()
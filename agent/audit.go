@@ -0,0 +1,174 @@
+package agent
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// File in the data directory that records actions performed on request of the hub
+	auditLogName = "audit.log"
+	// Size at which the audit log is rotated to audit.log.1
+	auditLogMaxSize = 5 * 1024 * 1024
+	// Number of rotated files kept (audit.log.1 is the newest); the oldest is removed on rotation
+	auditLogBackups = 5
+)
+
+var errAuditChainBroken = errors.New("audit log hash chain broken")
+
+// auditEntry is a single JSON line in the audit log. Hash is the SHA-256 of the
+// entry encoded with an empty Hash, and Prev is the hash of the entry before it,
+// so editing, inserting, or removing lines breaks the chain.
+type auditEntry struct {
+	Time   time.Time `json:"time"`
+	Origin string    `json:"origin"`
+	Kind   string    `json:"kind"`
+	Target string    `json:"target"`
+	Action string    `json:"action"`
+	Result string    `json:"result,omitempty"`
+	Error  string    `json:"error,omitempty"`
+	Prev   string    `json:"prev"`
+	Hash   string    `json:"hash"`
+}
+
+// auditLog appends hash-chained entries to a local file, independent of the hub connection.
+type auditLog struct {
+	sync.Mutex
+	path     string
+	maxSize  int64
+	lastHash string
+}
+
+// newAuditLog opens the audit log in the data directory and verifies its hash chain.
+// Returns nil if there is no data directory.
+func newAuditLog(dataDir string) *auditLog {
+	if dataDir == "" {
+		return nil
+	}
+	al := &auditLog{path: filepath.Join(dataDir, auditLogName), maxSize: auditLogMaxSize}
+	f, err := os.Open(al.path)
+	if err != nil {
+		return al
+	}
+	defer f.Close()
+	al.lastHash, err = verifyAuditChain(f, "")
+	if err != nil {
+		slog.Warn("Audit log verification failed", "path", al.path, "err", err)
+	}
+	return al
+}
+
+// newAuditEntry builds an entry for an action and its outcome.
+func newAuditEntry(origin, kind, target, action, result string, err error) auditEntry {
+	entry := auditEntry{Origin: origin, Kind: kind, Target: target, Action: action, Result: result}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	return entry
+}
+
+// hashAuditEntry returns the hex SHA-256 of the entry encoded with an empty Hash.
+func hashAuditEntry(entry auditEntry) (string, error) {
+	entry.Hash = ""
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// record appends an entry to the audit log. Failures are logged rather than
+// returned so that a full or read-only disk does not change the action's outcome.
+func (al *auditLog) record(entry auditEntry) {
+	if al == nil {
+		return
+	}
+	al.Lock()
+	defer al.Unlock()
+
+	entry.Time = time.Now().UTC()
+	entry.Prev = al.lastHash
+	hash, err := hashAuditEntry(entry)
+	if err != nil {
+		slog.Warn("Failed to write audit log", "err", err)
+		return
+	}
+	entry.Hash = hash
+	line, err := json.Marshal(entry)
+	if err != nil {
+		slog.Warn("Failed to write audit log", "err", err)
+		return
+	}
+
+	// rotate before the file grows past the limit; the chain continues in the new file
+	if info, err := os.Stat(al.path); err == nil && info.Size()+int64(len(line)) >= al.maxSize {
+		if err := al.rotate(); err != nil {
+			slog.Warn("Failed to rotate audit log", "err", err)
+		}
+	}
+
+	f, err := os.OpenFile(al.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		slog.Warn("Failed to write audit log", "err", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		slog.Warn("Failed to write audit log", "err", err)
+		return
+	}
+	al.lastHash = entry.Hash
+}
+
+// rotate shifts audit.log.N to audit.log.N+1, removing the oldest beyond
+// auditLogBackups, and moves the current file to audit.log.1.
+func (al *auditLog) rotate() error {
+	for i := auditLogBackups - 1; i > 0; i-- {
+		err := os.Rename(fmt.Sprintf("%s.%d", al.path, i), fmt.Sprintf("%s.%d", al.path, i+1))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return os.Rename(al.path, al.path+".1")
+}
+
+// verifyAuditChain checks each entry's hash and link to the previous entry and
+// returns the hash of the last entry. If prev is empty, the first entry's Prev
+// is trusted, which allows verifying a file whose predecessor was rotated away.
+func verifyAuditChain(r io.Reader, prev string) (string, error) {
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		var entry auditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return prev, fmt.Errorf("%w: line %d: %v", errAuditChainBroken, lineNum, err)
+		}
+		if lineNum > 1 || prev != "" {
+			if entry.Prev != prev {
+				return prev, fmt.Errorf("%w: line %d: unexpected previous hash", errAuditChainBroken, lineNum)
+			}
+		}
+		hash, err := hashAuditEntry(entry)
+		if err != nil {
+			return prev, err
+		}
+		if hash != entry.Hash {
+			return prev, fmt.Errorf("%w: line %d: hash mismatch", errAuditChainBroken, lineNum)
+		}
+		prev = entry.Hash
+	}
+	return prev, scanner.Err()
+}
@@ -0,0 +1,193 @@
+//go:build testing
+// +build testing
+
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/henrygd/beszel/internal/common"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, auditLogName)
+
+	al := newAuditLog(dir)
+	require.NotNil(t, al)
+	al.record(newAuditEntry("websocket hub.example.com", "service", "nginx.service", "restart", "restarted", nil))
+	al.record(newAuditEntry("ssh 10.0.0.2:51234", "container", "abc123", "stop", "", errors.New("not allowed")))
+
+	t.Run("chain verifies", func(t *testing.T) {
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		defer f.Close()
+		last, err := verifyAuditChain(f, "")
+		require.NoError(t, err)
+		assert.Equal(t, al.lastHash, last)
+	})
+
+	t.Run("file permissions", func(t *testing.T) {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	})
+
+	t.Run("reopened log continues chain", func(t *testing.T) {
+		reopened := newAuditLog(dir)
+		assert.Equal(t, al.lastHash, reopened.lastHash)
+		reopened.record(newAuditEntry("websocket hub.example.com", "service", "nginx.service", "start", "started", nil))
+
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		defer f.Close()
+		_, err = verifyAuditChain(f, "")
+		assert.NoError(t, err)
+	})
+
+	tests := []struct {
+		name   string
+		tamper func(lines []string) []string
+	}{
+		{
+			name: "edited entry",
+			tamper: func(lines []string) []string {
+				lines[0] = strings.Replace(lines[0], "restart", "stop", 1)
+				return lines
+			},
+		},
+		{
+			name: "removed entry",
+			tamper: func(lines []string) []string {
+				return append(lines[:1], lines[2:]...)
+			},
+		},
+		{
+			name: "reordered entries",
+			tamper: func(lines []string) []string {
+				lines[1], lines[2] = lines[2], lines[1]
+				return lines
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+			require.Len(t, lines, 3)
+
+			tampered := strings.Join(tt.tamper(lines), "\n")
+			_, err = verifyAuditChain(strings.NewReader(tampered), "")
+			assert.True(t, errors.Is(err, errAuditChainBroken), "expected broken chain, got %v", err)
+		})
+	}
+}
+
+func TestAuditLogNoDataDir(t *testing.T) {
+	al := newAuditLog("")
+	assert.Nil(t, al)
+	// recording on a nil log is a no-op
+	al.record(newAuditEntry("ssh", "service", "nginx.service", "stop", "", nil))
+}
+
+func TestAuditLogRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, auditLogName)
+
+	al := newAuditLog(dir)
+	// rotate before every write once the file exists
+	al.maxSize = 1
+	for i := range auditLogBackups + 3 {
+		al.record(newAuditEntry("ssh", "service", "nginx.service", "restart", fmt.Sprint(i), nil))
+	}
+
+	// the oldest files beyond auditLogBackups are removed
+	_, err := os.Stat(fmt.Sprintf("%s.%d", path, auditLogBackups+1))
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+
+	// the chain continues from the oldest kept file to the current one
+	prev := ""
+	for i := auditLogBackups; i >= 0; i-- {
+		name := path
+		if i > 0 {
+			name = fmt.Sprintf("%s.%d", path, i)
+		}
+		f, err := os.Open(name)
+		require.NoError(t, err)
+		prev, err = verifyAuditChain(f, prev)
+		f.Close()
+		require.NoError(t, err, "file %s", name)
+	}
+	assert.Equal(t, al.lastHash, prev)
+}
+
+func TestAuditRejectedActions(t *testing.T) {
+	dir := t.TempDir()
+	a := &Agent{auditLog: newAuditLog(dir)}
+
+	validRequest, err := cbor.Marshal(common.SystemdActionRequest{ServiceName: "nginx.service", Action: "restart"})
+	require.NoError(t, err)
+	containerRequest, err := cbor.Marshal(common.ContainerActionRequest{ContainerID: "abc123", Action: "stop"})
+	require.NoError(t, err)
+
+	requests := []struct {
+		handler RequestHandler
+		data    []byte
+	}{
+		// no systemd or docker manager
+		{&SystemdServiceActionHandler{}, validRequest},
+		{&ContainerActionHandler{}, containerRequest},
+		// undecodable request
+		{&SystemdServiceActionHandler{}, []byte{0xff}},
+	}
+	for _, r := range requests {
+		hctx := &HandlerContext{
+			Agent:   a,
+			Request: &common.HubRequest[cbor.RawMessage]{Data: r.data},
+			Origin:  "ssh 10.0.0.2:51234",
+			SendResponse: func(data any, requestID *uint32) error {
+				return nil
+			},
+		}
+		assert.Error(t, r.handler.Handle(hctx))
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, auditLogName))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 3)
+
+	var entries []auditEntry
+	for _, line := range lines {
+		var entry auditEntry
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+
+	assert.Equal(t, "service", entries[0].Kind)
+	assert.Equal(t, "nginx.service", entries[0].Target)
+	assert.Equal(t, "restart", entries[0].Action)
+	assert.Equal(t, errors.ErrUnsupported.Error(), entries[0].Error)
+	assert.Equal(t, "ssh 10.0.0.2:51234", entries[0].Origin)
+
+	assert.Equal(t, "container", entries[1].Kind)
+	assert.Equal(t, "abc123", entries[1].Target)
+	assert.Equal(t, errors.ErrUnsupported.Error(), entries[1].Error)
+
+	assert.Equal(t, "service", entries[2].Kind)
+	assert.Empty(t, entries[2].Target)
+	assert.NotEmpty(t, entries[2].Error)
+}
@@ -112,6 +112,10 @@ func (client *WebSocketClient) getOptions() *gws.ClientOption {
 			"X-Beszel":   []string{beszel.Version},
 		},
 	}
+	// request permessage-deflate, which the hub accepts if its WS_COMPRESSION is also set
+	if compression, _ := GetEnv("WS_COMPRESSION"); compression == "true" {
+		client.options.PermessageDeflate = gws.PermessageDeflate{Enabled: true}
+	}
 	return client.options
 }
 
@@ -168,6 +172,10 @@ func (client *WebSocketClient) OnMessage(conn *gws.Conn, message *gws.Message) {
 
 	if err := client.handleHubRequest(&HubRequest, HubRequest.Id); err != nil {
 		slog.Error("Error handling message", "err", err)
+		// let the hub know the request failed instead of waiting for a timeout
+		if HubRequest.Id != nil {
+			_ = client.sendMessage(common.AgentResponse{Id: HubRequest.Id, Error: err.Error()})
+		}
 	}
 }
 
@@ -236,6 +244,7 @@ func (client *WebSocketClient) handleHubRequest(msg *common.HubRequest[cbor.RawM
 		Request:      msg,
 		RequestID:    requestID,
 		HubVerified:  client.hubVerified,
+		Origin:       "websocket " + client.hubURL.Host,
 		SendResponse: client.sendResponse,
 	}
 	return client.agent.handlerRegistry.Handle(ctx)
@@ -15,6 +15,7 @@ import (
 	"os"
 	"path"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -25,6 +26,8 @@ import (
 	"github.com/blang/semver"
 )
 
+var errContainerActionDisabled = errors.New("container action not allowed")
+
 // ansiEscapePattern matches ANSI escape sequences (colors, cursor movement, etc.)
 // This includes CSI sequences like \x1b[...m and simple escapes like \x1b[K
 var ansiEscapePattern = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]|\x1b\][^\x07]*\x07|\x1b[@-Z\\-_]`)
@@ -61,6 +64,7 @@ type dockerManager struct {
 	apiStats            *container.ApiStats         // Reusable API stats object
 	excludeContainers   []string                    // Patterns to exclude containers by name
 	usingPodman         bool                        // Whether the Docker Engine API is running on Podman
+	containerActions    map[string][]string         // Name patterns -> actions allowed by CONTAINER_ACTIONS
 
 	// Cache-time-aware tracking for CPU stats (similar to cpu.go)
 	// Maps cache time intervals to container-specific CPU usage tracking
@@ -554,6 +558,7 @@ func newDockerManager() *dockerManager {
 		apiContainerList:  []*container.ApiInfo{},
 		apiStats:          &container.ApiStats{},
 		excludeContainers: excludeContainers,
+		containerActions:  parseActionAllowlist("CONTAINER_ACTIONS", container.Actions),
 
 		// Initialize cache-time-aware tracking structures
 		lastCpuContainer:    make(map[uint16]map[string]uint64),
@@ -670,6 +675,12 @@ func (dm *dockerManager) getContainerInfo(ctx context.Context, containerID strin
 	if config, ok := containerInfo["Config"].(map[string]any); ok {
 		delete(config, "Env")
 	}
+	// Let the hub know which actions CONTAINER_ACTIONS allows for this container
+	if name, ok := containerInfo["Name"].(string); ok {
+		if actions := dm.allowedContainerActions(strings.TrimPrefix(name, "/")); len(actions) > 0 {
+			containerInfo["AllowedActions"] = actions
+		}
+	}
 
 	return json.Marshal(containerInfo)
 }
@@ -707,6 +718,92 @@ func (dm *dockerManager) getLogs(ctx context.Context, containerID string) (strin
 	return logs, nil
 }
 
+// allowedContainerActions returns the actions CONTAINER_ACTIONS allows for a container name
+func (dm *dockerManager) allowedContainerActions(name string) []string {
+	var actions []string
+	for _, action := range container.Actions {
+		if dm.isContainerActionAllowed(name, action) {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// isContainerActionAllowed checks if any CONTAINER_ACTIONS pattern matching the name allows the action
+func (dm *dockerManager) isContainerActionAllowed(name, action string) bool {
+	if !slices.Contains(container.Actions, action) {
+		return false
+	}
+	for pattern, actions := range dm.containerActions {
+		if match, _ := path.Match(pattern, name); !match {
+			continue
+		}
+		if slices.Contains(actions, "*") || slices.Contains(actions, action) {
+			return true
+		}
+	}
+	return false
+}
+
+// performContainerAction starts, stops or restarts a container and returns its new state.
+// The container is looked up with the inspect endpoint rather than the stats map so that
+// stopped containers, which are not in the stats map, can be started.
+func (dm *dockerManager) performContainerAction(ctx context.Context, containerID, action string) (string, error) {
+	name, _, err := dm.inspectContainerState(ctx, containerID)
+	if err != nil {
+		return "", err
+	}
+	if dm.shouldExcludeContainer(name) {
+		return "", fmt.Errorf("%w: %s is excluded", errContainerActionDisabled, name)
+	}
+	if !dm.isContainerActionAllowed(name, action) {
+		return "", fmt.Errorf("%w: %s %s", errContainerActionDisabled, action, name)
+	}
+
+	endpoint := fmt.Sprintf("http://localhost/containers/%s/%s", containerID, action)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+
+	// stopping a container can take longer than DOCKER_TIMEOUT, so rely on ctx instead
+	client := *dm.client
+	client.Timeout = 0
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	// 304 means the container was already started / stopped
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotModified {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("container %s request failed: %s: %s", action, resp.Status, strings.TrimSpace(string(body)))
+	}
+	slog.Info("Container action", "container", name, "action", action)
+
+	_, status, err := dm.inspectContainerState(ctx, containerID)
+	return status, err
+}
+
+// inspectContainerState returns the name and state of a container, including stopped ones
+func (dm *dockerManager) inspectContainerState(ctx context.Context, containerID string) (name, status string, err error) {
+	info, err := dm.getContainerInfo(ctx, containerID)
+	if err != nil {
+		return "", "", err
+	}
+	var inspect struct {
+		Name  string
+		State struct {
+			Status string
+		}
+	}
+	if err := json.Unmarshal(info, &inspect); err != nil {
+		return "", "", err
+	}
+	return strings.TrimPrefix(inspect.Name, "/"), inspect.State.Status, nil
+}
+
 func decodeDockerLogStream(reader io.Reader, builder *strings.Builder, multiplexed bool) error {
 	if !multiplexed {
 		_, err := io.Copy(builder, io.LimitReader(reader, maxTotalLogSize))
@@ -5,7 +5,12 @@ package agent
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -1186,6 +1191,113 @@ func TestShouldExcludeContainer(t *testing.T) {
 	}
 }
 
+func TestIsContainerActionAllowed(t *testing.T) {
+	dm := &dockerManager{
+		containerActions: map[string][]string{
+			"nginx":  {"restart"},
+			"test-*": {"*"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		containerName string
+		action        string
+		expected      bool
+	}{
+		{"exact match allowed action", "nginx", "restart", true},
+		{"exact match other action", "nginx", "stop", false},
+		{"wildcard pattern allows all", "test-web", "stop", true},
+		{"wildcard pattern invalid action", "test-web", "remove", false},
+		{"no matching pattern", "prod-web", "restart", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, dm.isContainerActionAllowed(tt.containerName, tt.action))
+		})
+	}
+
+	assert.Equal(t, []string{"start", "stop", "restart"}, dm.allowedContainerActions("test-api"))
+	assert.Nil(t, dm.allowedContainerActions("prod-web"))
+}
+
+func TestPerformContainerAction(t *testing.T) {
+	// fake Docker API with one running and one stopped container
+	states := map[string]string{"running1": "running", "stopped1": "exited", "excluded1": "exited"}
+	names := map[string]string{"running1": "web", "stopped1": "worker", "excluded1": "backup"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if len(parts) != 3 || parts[0] != "containers" {
+			http.NotFound(w, r)
+			return
+		}
+		id, op := parts[1], parts[2]
+		if _, ok := states[id]; !ok {
+			http.Error(w, `{"message":"No such container"}`, http.StatusNotFound)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && op == "json":
+			json.NewEncoder(w).Encode(map[string]any{
+				"Id":    id,
+				"Name":  "/" + names[id],
+				"State": map[string]string{"Status": states[id]},
+			})
+		case r.Method == http.MethodPost && (op == "start" || op == "restart"):
+			states[id] = "running"
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPost && op == "stop":
+			states[id] = "exited"
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dm := &dockerManager{
+		client: &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return net.Dial("tcp", server.Listener.Addr().String())
+			},
+		}},
+		containerActions:  map[string][]string{"web": {"stop"}, "worker": {"start"}, "backup": {"*"}},
+		excludeContainers: []string{"backup"},
+		// stopped containers are not in the stats map
+		containerStatsMap: map[string]*container.Stats{},
+	}
+	ctx := context.Background()
+
+	t.Run("start stopped container", func(t *testing.T) {
+		status, err := dm.performContainerAction(ctx, "stopped1", "start")
+		require.NoError(t, err)
+		assert.Equal(t, "running", status)
+	})
+
+	t.Run("stop running container", func(t *testing.T) {
+		status, err := dm.performContainerAction(ctx, "running1", "stop")
+		require.NoError(t, err)
+		assert.Equal(t, "exited", status)
+	})
+
+	t.Run("action not allowed", func(t *testing.T) {
+		_, err := dm.performContainerAction(ctx, "running1", "restart")
+		assert.True(t, errors.Is(err, errContainerActionDisabled))
+	})
+
+	t.Run("excluded container", func(t *testing.T) {
+		_, err := dm.performContainerAction(ctx, "excluded1", "start")
+		assert.True(t, errors.Is(err, errContainerActionDisabled))
+		assert.Equal(t, "exited", states["excluded1"])
+	})
+
+	t.Run("unknown container", func(t *testing.T) {
+		_, err := dm.performContainerAction(ctx, "missing", "start")
+		assert.ErrorContains(t, err, "404")
+	})
+}
+
 func TestAnsiEscapePattern(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/fxamacker/cbor/v2"
 	"github.com/henrygd/beszel/internal/common"
@@ -19,6 +20,8 @@ type HandlerContext struct {
 	Request     *common.HubRequest[cbor.RawMessage]
 	RequestID   *uint32
 	HubVerified bool
+	// Origin describes the authenticated connection the request arrived on
+	Origin string
 	// SendResponse abstracts how a handler sends responses (WS or SSH)
 	SendResponse func(data any, requestID *uint32) error
 }
@@ -51,6 +54,8 @@ func NewHandlerRegistry() *HandlerRegistry {
 	registry.Register(common.GetContainerInfo, &GetContainerInfoHandler{})
 	registry.Register(common.GetSmartData, &GetSmartDataHandler{})
 	registry.Register(common.GetSystemdInfo, &GetSystemdInfoHandler{})
+	registry.Register(common.SystemdServiceAction, &SystemdServiceActionHandler{})
+	registry.Register(common.ContainerAction, &ContainerActionHandler{})
 
 	return registry
 }
@@ -161,6 +166,42 @@ func (h *GetContainerInfoHandler) Handle(hctx *HandlerContext) error {
 ////////////////////////////////////////////////////////////////////////////
 ////////////////////////////////////////////////////////////////////////////
 
+// ContainerActionHandler handles container start / stop / restart requests
+type ContainerActionHandler struct{}
+
+func (h *ContainerActionHandler) Handle(hctx *HandlerContext) error {
+	var req common.ContainerActionRequest
+	status, err := h.perform(hctx, &req)
+	// rejected requests are recorded too, with whatever target could be decoded
+	hctx.Agent.auditLog.record(newAuditEntry(hctx.Origin, "container", req.ContainerID, req.Action, status, err))
+	if err != nil {
+		slog.Warn("Container action failed", "container", req.ContainerID, "action", req.Action, "err", err)
+		return err
+	}
+
+	return hctx.SendResponse(&common.ContainerActionResponse{Status: status}, hctx.RequestID)
+}
+
+// perform decodes and validates the request and runs the action
+func (h *ContainerActionHandler) perform(hctx *HandlerContext, req *common.ContainerActionRequest) (string, error) {
+	if err := cbor.Unmarshal(hctx.Request.Data, req); err != nil {
+		return "", err
+	}
+	if hctx.Agent.dockerManager == nil {
+		return "", errors.ErrUnsupported
+	}
+	if req.ContainerID == "" || req.Action == "" {
+		return "", errors.New("container id and action are required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return hctx.Agent.dockerManager.performContainerAction(ctx, req.ContainerID, req.Action)
+}
+
+////////////////////////////////////////////////////////////////////////////
+////////////////////////////////////////////////////////////////////////////
+
 // GetSmartDataHandler handles SMART data requests
 type GetSmartDataHandler struct{}
 
@@ -203,3 +244,36 @@ func (h *GetSystemdInfoHandler) Handle(hctx *HandlerContext) error {
 
 	return hctx.SendResponse(details, hctx.RequestID)
 }
+
+////////////////////////////////////////////////////////////////////////////
+////////////////////////////////////////////////////////////////////////////
+
+// SystemdServiceActionHandler handles start / stop / restart / enable / disable requests
+type SystemdServiceActionHandler struct{}
+
+func (h *SystemdServiceActionHandler) Handle(hctx *HandlerContext) error {
+	var req common.SystemdActionRequest
+	result, err := h.perform(hctx, &req)
+	// rejected requests are recorded too, with whatever target could be decoded
+	hctx.Agent.auditLog.record(newAuditEntry(hctx.Origin, "service", req.ServiceName, req.Action, result, err))
+	if err != nil {
+		slog.Warn("Service action failed", "service", req.ServiceName, "action", req.Action, "err", err)
+		return err
+	}
+
+	return hctx.SendResponse(&common.SystemdActionResponse{Result: result}, hctx.RequestID)
+}
+
+// perform decodes and validates the request and runs the action
+func (h *SystemdServiceActionHandler) perform(hctx *HandlerContext, req *common.SystemdActionRequest) (string, error) {
+	if err := cbor.Unmarshal(hctx.Request.Data, req); err != nil {
+		return "", err
+	}
+	if hctx.Agent.systemdManager == nil {
+		return "", errors.ErrUnsupported
+	}
+	if req.ServiceName == "" || req.Action == "" {
+		return "", errors.New("service name and action are required")
+	}
+	return hctx.Agent.systemdManager.performServiceAction(req.ServiceName, req.Action)
+}
@@ -169,12 +169,18 @@ func (a *Agent) handleSSHRequest(w io.Writer, req *common.HubRequest[cbor.RawMes
 		return cbor.NewEncoder(w).Encode(response)
 	}
 
+	origin := "ssh"
+	if s, ok := w.(ssh.Session); ok {
+		origin += " " + s.RemoteAddr().String()
+	}
+
 	ctx := &HandlerContext{
 		Client:       nil,
 		Agent:        a,
 		Request:      req,
 		RequestID:    nil,
 		HubVerified:  true,
+		Origin:       origin,
 		SendResponse: sshResponder,
 	}
 
@@ -1,9 +1,11 @@
 package agent
 
 import (
+	"bytes"
 	"context"
 	"crypto/ed25519"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"os"
@@ -13,6 +15,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/henrygd/beszel/internal/common"
 	"github.com/henrygd/beszel/internal/entities/container"
 	"github.com/henrygd/beszel/internal/entities/system"
 
@@ -603,3 +606,49 @@ func TestHubVersionCaching(t *testing.T) {
 	v3 := agent.getHubVersion("session3", ctx3)
 	assert.Equal(t, "0.13.0", v3.String())
 }
+
+// TestHandleSSHRequestHandlerError tests that a failing handler still writes an
+// AgentResponse so the hub gets the error instead of an empty session
+func TestHandleSSHRequestHandlerError(t *testing.T) {
+	agent := &Agent{handlerRegistry: NewHandlerRegistry()}
+
+	tests := []struct {
+		name          string
+		action        common.WebSocketAction
+		data          any
+		expectedError string
+	}{
+		{
+			name:          "service action without systemd",
+			action:        common.SystemdServiceAction,
+			data:          common.SystemdActionRequest{ServiceName: "nginx", Action: "restart"},
+			expectedError: errors.ErrUnsupported.Error(),
+		},
+		{
+			name:          "container action without docker",
+			action:        common.ContainerAction,
+			data:          common.ContainerActionRequest{ContainerID: "abc123", Action: "stop"},
+			expectedError: errors.ErrUnsupported.Error(),
+		},
+		{
+			name:          "unknown action",
+			action:        common.WebSocketAction(255),
+			expectedError: "unknown action: 255",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := cbor.Marshal(tt.data)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			err = agent.handleSSHRequest(&buf, &common.HubRequest[cbor.RawMessage]{Action: tt.action, Data: data})
+			require.NoError(t, err)
+
+			var resp common.AgentResponse
+			require.NoError(t, cbor.NewDecoder(&buf).Decode(&resp))
+			assert.Equal(t, tt.expectedError, resp.Error)
+		})
+	}
+}
@@ -5,10 +5,12 @@ package agent
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"maps"
 	"math"
 	"os"
+	"os/exec"
 	"strconv"
 	"strings"
 	"sync"
@@ -20,6 +22,19 @@ import (
 
 var errNoActiveTime = errors.New("no active time")
 
+// serviceNameSuffix is appended to service names from env vars that don't include it
+const serviceNameSuffix = ".service"
+
+// serviceNamesFoldCase is false because systemd unit names are case-sensitive
+const serviceNamesFoldCase = false
+
+// serviceJournalLines is the number of recent journal lines included in service details
+const serviceJournalLines = 50
+
+// serviceJournalTimeout limits how long journalctl may add to a service details request,
+// which must finish well within the hub's timeout
+const serviceJournalTimeout = 1500 * time.Millisecond
+
 // systemdManager manages the collection of systemd service statistics.
 type systemdManager struct {
 	sync.Mutex
@@ -27,6 +42,7 @@ type systemdManager struct {
 	isRunning       bool
 	hasFreshStats   bool
 	patterns        []string
+	actions         map[string][]string // allowed actions per unit name
 }
 
 // isSystemdAvailable checks if systemd is used on the system to avoid unnecessary connection attempts (#1548)
@@ -68,6 +84,7 @@ func newSystemdManager() (*systemdManager, error) {
 	manager := &systemdManager{
 		serviceStatsMap: make(map[string]*systemd.Service),
 		patterns:        getServicePatterns(),
+		actions:         getServiceActions(),
 	}
 
 	manager.startWorker(conn)
@@ -260,9 +277,119 @@ func (sm *systemdManager) getServiceDetails(serviceName string) (systemd.Service
 		}
 	}
 
+	// Add actions allowed by SERVICE_ACTIONS so the hub can offer them
+	if allowed := sm.allowedActions(unitName); len(allowed) > 0 {
+		details["AllowedActions"] = allowed
+	}
+
+	// Add recent journal lines if journalctl is available (not the case in containers)
+	if logs, err := getServiceJournal(ctx, unitName); err == nil && logs != "" {
+		details["Logs"] = logs
+	} else if err != nil {
+		slog.Debug("Error reading service journal", "unit", unitName, "err", err)
+	}
+
 	return details, nil
 }
 
+// getServiceJournal returns the most recent journal lines for a systemd unit.
+func getServiceJournal(ctx context.Context, unitName string) (string, error) {
+	journalctl, err := exec.LookPath("journalctl")
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, serviceJournalTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, journalctl,
+		"--unit="+unitName,
+		"--lines="+strconv.Itoa(serviceJournalLines),
+		"--no-pager",
+		"--quiet",
+		"--output=short-iso",
+	)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// performServiceAction runs a start, stop, restart, enable or disable action on a
+// service if it is allowed by SERVICE_ACTIONS. Returns the systemd job result.
+func (sm *systemdManager) performServiceAction(serviceName, action string) (string, error) {
+	unitName := serviceName
+	if !strings.HasSuffix(unitName, serviceNameSuffix) {
+		unitName += serviceNameSuffix
+	}
+	if !sm.isActionAllowed(unitName, action) {
+		return "", fmt.Errorf("%w: %s %s", errServiceActionDisabled, action, unitName)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	conn, err := dbus.NewSystemConnectionContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	result := "done"
+	switch action {
+	case systemd.ActionStart, systemd.ActionStop, systemd.ActionRestart:
+		resultCh := make(chan string, 1)
+		var jobErr error
+		switch action {
+		case systemd.ActionStart:
+			_, jobErr = conn.StartUnitContext(ctx, unitName, "replace", resultCh)
+		case systemd.ActionStop:
+			_, jobErr = conn.StopUnitContext(ctx, unitName, "replace", resultCh)
+		case systemd.ActionRestart:
+			_, jobErr = conn.RestartUnitContext(ctx, unitName, "replace", resultCh)
+		}
+		if jobErr != nil {
+			return "", jobErr
+		}
+		select {
+		case result = <-resultCh:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	case systemd.ActionEnable:
+		if _, _, err := conn.EnableUnitFilesContext(ctx, []string{unitName}, false, false); err != nil {
+			return "", err
+		}
+		if err := conn.ReloadContext(ctx); err != nil {
+			return "", err
+		}
+	case systemd.ActionDisable:
+		if _, err := conn.DisableUnitFilesContext(ctx, []string{unitName}, false); err != nil {
+			return "", err
+		}
+		if err := conn.ReloadContext(ctx); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("unknown service action: %s", action)
+	}
+
+	slog.Info("Service action", "unit", unitName, "action", action, "result", result)
+
+	// update stats for the unit so the hub receives the new state on the next request
+	if units, err := conn.ListUnitsByNamesContext(ctx, []string{unitName}); err == nil {
+		for _, unit := range units {
+			if _, err := sm.updateServiceStats(conn, unit); err == nil {
+				sm.hasFreshStats = true
+			}
+		}
+	}
+
+	if result != "done" {
+		return result, fmt.Errorf("%s %s: job %s", action, unitName, result)
+	}
+	return result, nil
+}
+
 // unescapeServiceName unescapes systemd service names that contain C-style escape sequences like \x2d
 func unescapeServiceName(name string) string {
 	if !strings.Contains(name, "\\x") {
@@ -0,0 +1,61 @@
+//go:build linux || windows
+
+package agent
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/henrygd/beszel/internal/entities/systemd"
+)
+
+var errServiceActionDisabled = errors.New("service action not allowed")
+
+// allowedActions returns the actions that may be performed on the unit.
+func (sm *systemdManager) allowedActions(unitName string) []string {
+	var allowed []string
+	for _, action := range systemd.ServiceActions {
+		if sm.isActionAllowed(unitName, action) {
+			allowed = append(allowed, action)
+		}
+	}
+	return allowed
+}
+
+// isActionAllowed reports whether the action may be performed on the unit.
+func (sm *systemdManager) isActionAllowed(unitName, action string) bool {
+	if !slices.Contains(systemd.ServiceActions, action) {
+		return false
+	}
+	allowed := sm.actions[actionKey(unitName)]
+	return slices.Contains(allowed, "*") || slices.Contains(allowed, action)
+}
+
+// actionKey returns the name used to look up a unit in the allowed actions,
+// lowercased on platforms where service names are case-insensitive.
+func actionKey(unitName string) string {
+	if serviceNamesFoldCase {
+		return strings.ToLower(unitName)
+	}
+	return unitName
+}
+
+// getServiceActions returns the actions allowed per service unit.
+// It reads from the SERVICE_ACTIONS environment variable (e.g.
+// "nginx:start|restart,php-fpm:*"). No actions are allowed if it is not set.
+func getServiceActions() map[string][]string {
+	actions := make(map[string][]string)
+	for name, allowed := range parseActionAllowlist("SERVICE_ACTIONS", systemd.ServiceActions) {
+		if !strings.HasSuffix(name, serviceNameSuffix) {
+			name += serviceNameSuffix
+		}
+		name = actionKey(name)
+		for _, action := range allowed {
+			if !slices.Contains(actions[name], action) {
+				actions[name] = append(actions[name], action)
+			}
+		}
+	}
+	return actions
+}
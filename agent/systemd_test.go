@@ -186,3 +186,69 @@ func TestGetServicePatterns(t *testing.T) {
 		})
 	}
 }
+
+func TestGetServiceActions(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      string
+		expected map[string][]string
+	}{
+		{
+			name:     "no actions when env var not set",
+			env:      "",
+			expected: map[string][]string{},
+		},
+		{
+			name: "single service with multiple actions",
+			env:  "nginx:restart|start",
+			expected: map[string][]string{
+				"nginx.service": {"restart", "start"},
+			},
+		},
+		{
+			name: "multiple services with suffix, wildcard, and whitespace",
+			env:  " nginx.service : restart , php-fpm:* ",
+			expected: map[string][]string{
+				"nginx.service":   {"restart"},
+				"php-fpm.service": {"*"},
+			},
+		},
+		{
+			name: "invalid entries and actions are skipped",
+			env:  "nginx,:restart,docker:restart|reboot|RESTART,sshd:stop",
+			expected: map[string][]string{
+				"docker.service": {"restart"},
+				"sshd.service":   {"stop"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Unsetenv("SERVICE_ACTIONS")
+			if tt.env != "" {
+				t.Setenv("BESZEL_AGENT_SERVICE_ACTIONS", tt.env)
+			}
+			assert.Equal(t, tt.expected, getServiceActions())
+		})
+	}
+}
+
+func TestIsActionAllowed(t *testing.T) {
+	sm := &systemdManager{
+		actions: map[string][]string{
+			"nginx.service":   {"restart", "start"},
+			"php-fpm.service": {"*"},
+		},
+	}
+
+	assert.True(t, sm.isActionAllowed("nginx.service", "restart"))
+	assert.True(t, sm.isActionAllowed("nginx.service", "start"))
+	assert.False(t, sm.isActionAllowed("nginx.service", "stop"))
+	assert.True(t, sm.isActionAllowed("php-fpm.service", "disable"))
+	assert.False(t, sm.isActionAllowed("php-fpm.service", "reboot"))
+	assert.False(t, sm.isActionAllowed("sshd.service", "restart"))
+
+	assert.Equal(t, []string{"start", "restart"}, sm.allowedActions("nginx.service"))
+	assert.Nil(t, sm.allowedActions("sshd.service"))
+}
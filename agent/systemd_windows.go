@@ -0,0 +1,458 @@
+//go:build windows
+
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/henrygd/beszel/internal/entities/systemd"
+	"github.com/shirou/gopsutil/v4/process"
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// serviceNameSuffix is empty because Windows service names have no suffix
+const serviceNameSuffix = ""
+
+// serviceNamesFoldCase is true because Windows service names are case-insensitive
+const serviceNamesFoldCase = true
+
+var (
+	errServiceSkipped       = errors.New("service skipped")
+	errServiceActionTimeout = errors.New("timed out waiting for service")
+)
+
+// systemdManager manages the collection of Windows service statistics.
+// It keeps the systemd name so services are handled the same way on all platforms.
+type systemdManager struct {
+	sync.Mutex
+	serviceStatsMap map[string]*systemd.Service
+	isRunning       bool
+	hasFreshStats   bool
+	patterns        []string
+	actions         map[string][]string // allowed actions per service name
+}
+
+// newSystemdManager creates a new systemdManager using the Windows Service Control Manager.
+func newSystemdManager() (*systemdManager, error) {
+	if skipSystemd, _ := GetEnv("SKIP_SYSTEMD"); skipSystemd == "true" {
+		return nil, nil
+	}
+
+	m, err := mgr.Connect()
+	if err != nil {
+		slog.Debug("Error connecting to service control manager", "err", err)
+		return nil, err
+	}
+	_ = m.Disconnect()
+
+	manager := &systemdManager{
+		serviceStatsMap: make(map[string]*systemd.Service),
+		patterns:        getWindowsServicePatterns(),
+		actions:         getServiceActions(),
+	}
+
+	manager.startWorker()
+
+	return manager, nil
+}
+
+func (sm *systemdManager) startWorker() {
+	if sm.isRunning {
+		return
+	}
+	sm.isRunning = true
+	// prime the service stats map with the current services
+	_ = sm.getServiceStats(nil, true)
+	// update the services every 10 minutes
+	go func() {
+		for {
+			time.Sleep(time.Minute * 10)
+			_ = sm.getServiceStats(nil, true)
+		}
+	}()
+}
+
+// getServiceStatsCount returns the number of Windows services.
+func (sm *systemdManager) getServiceStatsCount() int {
+	return len(sm.serviceStatsMap)
+}
+
+// getFailedServiceCount returns the number of Windows services in a failed state.
+func (sm *systemdManager) getFailedServiceCount() uint16 {
+	sm.Lock()
+	defer sm.Unlock()
+	count := uint16(0)
+	for _, service := range sm.serviceStatsMap {
+		if service.State == systemd.StatusFailed {
+			count++
+		}
+	}
+	return count
+}
+
+// getServiceStats collects statistics for Windows services.
+func (sm *systemdManager) getServiceStats(_ any, refresh bool) []*systemd.Service {
+	var services []*systemd.Service
+
+	if !refresh {
+		sm.Lock()
+		defer sm.Unlock()
+		for _, service := range sm.serviceStatsMap {
+			services = append(services, service)
+		}
+		sm.hasFreshStats = false
+		return services
+	}
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return nil
+	}
+	defer m.Disconnect()
+
+	names, err := m.ListServices()
+	if err != nil {
+		slog.Error("Error listing Windows services", "err", err)
+		return nil
+	}
+
+	for _, name := range names {
+		if !sm.matchesPatterns(name) {
+			continue
+		}
+		service, err := sm.updateServiceStats(m, name)
+		if err != nil {
+			continue
+		}
+		services = append(services, service)
+	}
+	sm.hasFreshStats = true
+	return services
+}
+
+// updateServiceStats updates the statistics for a single Windows service.
+func (sm *systemdManager) updateServiceStats(m *mgr.Mgr, name string) (*systemd.Service, error) {
+	s, err := m.OpenService(name)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+
+	status, err := s.Query()
+	if err != nil {
+		return nil, err
+	}
+	config, err := s.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	sm.Lock()
+	defer sm.Unlock()
+
+	service, serviceExists := sm.serviceStatsMap[name]
+	if !serviceExists {
+		// without SERVICE_PATTERNS, skip stopped services that are not set to start
+		// automatically (similar to systemd units that have never been active)
+		if sm.patterns == nil && status.State == svc.Stopped && config.StartType != mgr.StartAutomatic {
+			return nil, errServiceSkipped
+		}
+		service = &systemd.Service{Name: name}
+		sm.serviceStatsMap[name] = service
+	}
+
+	service.State, service.Sub = parseWindowsServiceState(status, config)
+
+	// memory and cpu are only accurate for services running in their own process
+	var memUsage, cpuUsage uint64
+	if status.ProcessId != 0 && config.ServiceType&windows.SERVICE_WIN32_OWN_PROCESS != 0 {
+		if proc, err := process.NewProcess(int32(status.ProcessId)); err == nil {
+			if memInfo, err := proc.MemoryInfo(); err == nil {
+				memUsage = memInfo.RSS
+			}
+			if times, err := proc.Times(); err == nil {
+				cpuUsage = uint64((times.User + times.System) * float64(time.Second))
+			}
+		}
+	}
+
+	service.Mem = memUsage
+	if memUsage > service.MemPeak {
+		service.MemPeak = memUsage
+	}
+	service.UpdateCPUPercent(cpuUsage)
+
+	return service, nil
+}
+
+// getServiceDetails collects extended information for a specific Windows service.
+// Keys match systemd property names so the hub can display them the same way.
+func (sm *systemdManager) getServiceDetails(serviceName string) (systemd.ServiceDetails, error) {
+	m, err := mgr.Connect()
+	if err != nil {
+		return nil, err
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+
+	status, err := s.Query()
+	if err != nil {
+		return nil, err
+	}
+	config, err := s.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	state, subState := parseWindowsServiceState(status, config)
+
+	description := config.Description
+	if description == "" {
+		description = config.DisplayName
+	}
+
+	details := systemd.ServiceDetails{
+		"Id":            serviceName,
+		"Description":   description,
+		"LoadState":     "loaded",
+		"ActiveState":   windowsServiceStateString(state),
+		"SubState":      windowsServiceSubStateString(subState),
+		"UnitFileState": windowsStartTypeString(config),
+		"FragmentPath":  config.BinaryPathName,
+		"Requires":      config.Dependencies,
+		"CanStart":      status.State == svc.Stopped,
+		"CanStop":       status.Accepts&svc.AcceptStop != 0,
+		"CanReload":     false,
+	}
+	if status.ProcessId != 0 {
+		details["MainPID"] = status.ProcessId
+	}
+	if status.Win32ExitCode != 0 {
+		details["Result"] = fmt.Sprintf("exit-code %d", status.Win32ExitCode)
+	} else {
+		details["Result"] = "success"
+	}
+	sm.Lock()
+	if service, ok := sm.serviceStatsMap[serviceName]; ok {
+		details["MemoryCurrent"] = service.Mem
+		details["MemoryPeak"] = service.MemPeak
+	}
+	sm.Unlock()
+
+	if allowed := sm.allowedActions(serviceName); len(allowed) > 0 {
+		details["AllowedActions"] = allowed
+	}
+
+	return details, nil
+}
+
+// performServiceAction runs a start, stop, restart, enable or disable action on a
+// service if it is allowed by SERVICE_ACTIONS.
+func (sm *systemdManager) performServiceAction(serviceName, action string) (string, error) {
+	if !sm.isActionAllowed(serviceName, action) {
+		return "", fmt.Errorf("%w: %s %s", errServiceActionDisabled, action, serviceName)
+	}
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return "", err
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(serviceName)
+	if err != nil {
+		return "", err
+	}
+	defer s.Close()
+
+	deadline := time.Now().Add(30 * time.Second)
+
+	switch action {
+	case systemd.ActionStart:
+		err = startWindowsService(s, deadline)
+	case systemd.ActionStop:
+		err = stopWindowsService(s, deadline)
+	case systemd.ActionRestart:
+		if err = stopWindowsService(s, deadline); err == nil {
+			err = startWindowsService(s, deadline)
+		}
+	case systemd.ActionEnable, systemd.ActionDisable:
+		var config mgr.Config
+		if config, err = s.Config(); err == nil {
+			config.StartType = mgr.StartAutomatic
+			if action == systemd.ActionDisable {
+				config.StartType = mgr.StartDisabled
+			}
+			err = s.UpdateConfig(config)
+		}
+	default:
+		return "", fmt.Errorf("unknown service action: %s", action)
+	}
+
+	if errors.Is(err, errServiceActionTimeout) {
+		return "timeout", fmt.Errorf("%s %s: %w", action, serviceName, err)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	slog.Info("Service action", "service", serviceName, "action", action)
+
+	// update stats for the service so the hub receives the new state on the next request
+	if _, err := sm.updateServiceStats(m, serviceName); err == nil {
+		sm.hasFreshStats = true
+	}
+
+	return "done", nil
+}
+
+// startWindowsService starts the service if it is stopped and waits for it to run.
+func startWindowsService(s *mgr.Service, deadline time.Time) error {
+	status, err := s.Query()
+	if err != nil {
+		return err
+	}
+	if status.State == svc.Stopped {
+		if err := s.Start(); err != nil {
+			return err
+		}
+	}
+	return waitForWindowsServiceState(s, svc.Running, deadline)
+}
+
+// stopWindowsService stops the service if it is not stopped and waits for it to stop.
+func stopWindowsService(s *mgr.Service, deadline time.Time) error {
+	status, err := s.Query()
+	if err != nil {
+		return err
+	}
+	if status.State != svc.Stopped && status.State != svc.StopPending {
+		if _, err := s.Control(svc.Stop); err != nil {
+			return err
+		}
+	}
+	return waitForWindowsServiceState(s, svc.Stopped, deadline)
+}
+
+// waitForWindowsServiceState polls the service until it reaches the desired state.
+func waitForWindowsServiceState(s *mgr.Service, state svc.State, deadline time.Time) error {
+	for {
+		status, err := s.Query()
+		if err != nil {
+			return err
+		}
+		if status.State == state {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errServiceActionTimeout
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
+
+// matchesPatterns reports whether the service name matches SERVICE_PATTERNS.
+func (sm *systemdManager) matchesPatterns(name string) bool {
+	if sm.patterns == nil {
+		return true
+	}
+	name = strings.ToLower(name)
+	for _, pattern := range sm.patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// parseWindowsServiceState maps a Windows service status to systemd states.
+// Stopped services that are set to start automatically and exited with an error are failed.
+func parseWindowsServiceState(status svc.Status, config mgr.Config) (systemd.ServiceState, systemd.ServiceSubState) {
+	switch status.State {
+	case svc.Running:
+		return systemd.StatusActive, systemd.SubStateRunning
+	case svc.StartPending, svc.ContinuePending:
+		return systemd.StatusActivating, systemd.SubStateUnknown
+	case svc.StopPending, svc.PausePending:
+		return systemd.StatusDeactivating, systemd.SubStateUnknown
+	case svc.Stopped:
+		if config.StartType == mgr.StartAutomatic && status.Win32ExitCode != 0 {
+			return systemd.StatusFailed, systemd.SubStateFailed
+		}
+		return systemd.StatusInactive, systemd.SubStateDead
+	default:
+		return systemd.StatusInactive, systemd.SubStateUnknown
+	}
+}
+
+func windowsServiceStateString(state systemd.ServiceState) string {
+	switch state {
+	case systemd.StatusActive:
+		return "active"
+	case systemd.StatusFailed:
+		return "failed"
+	case systemd.StatusActivating:
+		return "activating"
+	case systemd.StatusDeactivating:
+		return "deactivating"
+	default:
+		return "inactive"
+	}
+}
+
+func windowsServiceSubStateString(subState systemd.ServiceSubState) string {
+	switch subState {
+	case systemd.SubStateRunning:
+		return "running"
+	case systemd.SubStateDead:
+		return "dead"
+	case systemd.SubStateFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// windowsStartTypeString returns the service start type in terms of systemd enablement.
+func windowsStartTypeString(config mgr.Config) string {
+	switch config.StartType {
+	case mgr.StartAutomatic:
+		if config.DelayedAutoStart {
+			return "enabled (delayed)"
+		}
+		return "enabled"
+	case mgr.StartDisabled:
+		return "disabled"
+	default:
+		return "manual"
+	}
+}
+
+// getWindowsServicePatterns returns the lowercase service name patterns from the
+// SERVICE_PATTERNS environment variable, or nil to include all services.
+func getWindowsServicePatterns() []string {
+	var patterns []string
+	if envPatterns, _ := GetEnv("SERVICE_PATTERNS"); envPatterns != "" {
+		for pattern := range strings.SplitSeq(envPatterns, ",") {
+			pattern = strings.ToLower(strings.TrimSpace(pattern))
+			if pattern == "" {
+				continue
+			}
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
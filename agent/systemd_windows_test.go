@@ -0,0 +1,165 @@
+//go:build windows && testing
+
+package agent
+
+import (
+	"os"
+	"testing"
+
+	"github.com/henrygd/beszel/internal/entities/systemd"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+func TestParseWindowsServiceState(t *testing.T) {
+	tests := []struct {
+		name             string
+		status           svc.Status
+		config           mgr.Config
+		expectedState    systemd.ServiceState
+		expectedSubState systemd.ServiceSubState
+	}{
+		{
+			name:             "running",
+			status:           svc.Status{State: svc.Running},
+			expectedState:    systemd.StatusActive,
+			expectedSubState: systemd.SubStateRunning,
+		},
+		{
+			name:             "start pending",
+			status:           svc.Status{State: svc.StartPending},
+			expectedState:    systemd.StatusActivating,
+			expectedSubState: systemd.SubStateUnknown,
+		},
+		{
+			name:             "continue pending",
+			status:           svc.Status{State: svc.ContinuePending},
+			expectedState:    systemd.StatusActivating,
+			expectedSubState: systemd.SubStateUnknown,
+		},
+		{
+			name:             "stop pending",
+			status:           svc.Status{State: svc.StopPending},
+			expectedState:    systemd.StatusDeactivating,
+			expectedSubState: systemd.SubStateUnknown,
+		},
+		{
+			name:             "stopped manual service with exit code is inactive",
+			status:           svc.Status{State: svc.Stopped, Win32ExitCode: 1},
+			config:           mgr.Config{StartType: mgr.StartManual},
+			expectedState:    systemd.StatusInactive,
+			expectedSubState: systemd.SubStateDead,
+		},
+		{
+			name:             "stopped automatic service without exit code is inactive",
+			status:           svc.Status{State: svc.Stopped},
+			config:           mgr.Config{StartType: mgr.StartAutomatic},
+			expectedState:    systemd.StatusInactive,
+			expectedSubState: systemd.SubStateDead,
+		},
+		{
+			name:             "stopped automatic service with exit code is failed",
+			status:           svc.Status{State: svc.Stopped, Win32ExitCode: 1067},
+			config:           mgr.Config{StartType: mgr.StartAutomatic},
+			expectedState:    systemd.StatusFailed,
+			expectedSubState: systemd.SubStateFailed,
+		},
+		{
+			name:             "paused",
+			status:           svc.Status{State: svc.Paused},
+			expectedState:    systemd.StatusInactive,
+			expectedSubState: systemd.SubStateUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, subState := parseWindowsServiceState(tt.status, tt.config)
+			assert.Equal(t, tt.expectedState, state)
+			assert.Equal(t, tt.expectedSubState, subState)
+		})
+	}
+}
+
+func TestWindowsStartTypeString(t *testing.T) {
+	tests := []struct {
+		config   mgr.Config
+		expected string
+	}{
+		{mgr.Config{StartType: mgr.StartAutomatic}, "enabled"},
+		{mgr.Config{StartType: mgr.StartAutomatic, DelayedAutoStart: true}, "enabled (delayed)"},
+		{mgr.Config{StartType: mgr.StartManual}, "manual"},
+		{mgr.Config{StartType: mgr.StartDisabled}, "disabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, windowsStartTypeString(tt.config))
+		})
+	}
+}
+
+func TestGetWindowsServicePatterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      string
+		expected []string
+	}{
+		{
+			name:     "nil when env var not set",
+			env:      "",
+			expected: nil,
+		},
+		{
+			name:     "patterns are lowercased and trimmed",
+			env:      " W3SVC , MSSQL* ",
+			expected: []string{"w3svc", "mssql*"},
+		},
+		{
+			name:     "empty entries are skipped",
+			env:      "wuauserv,,  ,Spooler",
+			expected: []string{"wuauserv", "spooler"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Unsetenv("SERVICE_PATTERNS")
+			t.Setenv("BESZEL_AGENT_SERVICE_PATTERNS", tt.env)
+			assert.Equal(t, tt.expected, getWindowsServicePatterns())
+		})
+	}
+}
+
+func TestMatchesPatterns(t *testing.T) {
+	t.Run("all services match without patterns", func(t *testing.T) {
+		sm := &systemdManager{}
+		assert.True(t, sm.matchesPatterns("W3SVC"))
+	})
+
+	t.Run("patterns match case-insensitively", func(t *testing.T) {
+		sm := &systemdManager{patterns: []string{"w3svc", "mssql*"}}
+		assert.True(t, sm.matchesPatterns("W3SVC"))
+		assert.True(t, sm.matchesPatterns("w3svc"))
+		assert.True(t, sm.matchesPatterns("MSSQL$SQLEXPRESS"))
+		assert.False(t, sm.matchesPatterns("Spooler"))
+	})
+}
+
+func TestWindowsServiceActionsCaseInsensitive(t *testing.T) {
+	os.Unsetenv("SERVICE_ACTIONS")
+	t.Setenv("BESZEL_AGENT_SERVICE_ACTIONS", "w3svc:restart,Spooler:start|stop,W3SVC:stop")
+
+	sm := &systemdManager{actions: getServiceActions()}
+	assert.Equal(t, map[string][]string{
+		"w3svc":   {"restart", "stop"},
+		"spooler": {"start", "stop"},
+	}, sm.actions)
+
+	assert.True(t, sm.isActionAllowed("W3SVC", "restart"))
+	assert.True(t, sm.isActionAllowed("w3svc", "stop"))
+	assert.True(t, sm.isActionAllowed("SPOOLER", "start"))
+	assert.False(t, sm.isActionAllowed("W3SVC", "disable"))
+	assert.Equal(t, []string{"stop", "restart"}, sm.allowedActions("W3SVC"))
+}
@@ -22,6 +22,10 @@ const (
 	GetSmartData
 	// Request detailed systemd service info from agent
 	GetSystemdInfo
+	// Perform an action (start, stop, restart, enable, disable) on a systemd service
+	SystemdServiceAction
+	// Start, stop or restart a container
+	ContainerAction
 	// Add new actions here...
 )
 
@@ -74,3 +78,23 @@ type ContainerInfoRequest struct {
 type SystemdInfoRequest struct {
 	ServiceName string `cbor:"0,keyasint"`
 }
+
+type SystemdActionRequest struct {
+	ServiceName string `cbor:"0,keyasint"`
+	Action      string `cbor:"1,keyasint"`
+}
+
+type SystemdActionResponse struct {
+	// Result is the systemd job result (done, failed, timeout, ...)
+	Result string `cbor:"0,keyasint"`
+}
+
+type ContainerActionRequest struct {
+	ContainerID string `cbor:"0,keyasint"`
+	Action      string `cbor:"1,keyasint"`
+}
+
+type ContainerActionResponse struct {
+	// Status is the container state after the action (running, exited, ...)
+	Status string `cbor:"0,keyasint"`
+}
@@ -145,3 +145,13 @@ type Stats struct {
 	PrevNet      prevNetStats `json:"-"`
 	PrevReadTime time.Time    `json:"-"`
 }
+
+// Actions that can be performed on a container
+const (
+	ActionStart   = "start"
+	ActionStop    = "stop"
+	ActionRestart = "restart"
+)
+
+// Actions lists all supported container actions
+var Actions = []string{ActionStart, ActionStop, ActionRestart}
@@ -29,6 +29,18 @@ const (
 	SubStateUnknown
 )
 
+// Actions that can be performed on a systemd service
+const (
+	ActionStart   = "start"
+	ActionStop    = "stop"
+	ActionRestart = "restart"
+	ActionEnable  = "enable"
+	ActionDisable = "disable"
+)
+
+// ServiceActions lists all supported service actions
+var ServiceActions = []string{ActionStart, ActionStop, ActionRestart, ActionEnable, ActionDisable}
+
 // ParseServiceStatus converts a string status to a ServiceStatus enum value
 func ParseServiceStatus(status string) ServiceState {
 	switch status {
@@ -2,6 +2,7 @@ package expirymap
 
 import (
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/pocketbase/pocketbase/tools/store"
@@ -15,6 +16,7 @@ type val[T any] struct {
 type ExpiryMap[T any] struct {
 	store           *store.Store[string, *val[T]]
 	cleanupInterval time.Duration
+	takeMu          sync.Mutex // serializes GetAndRemove so a key is only taken once
 }
 
 // New creates a new expiry map with custom cleanup interval
@@ -67,6 +69,18 @@ func (m *ExpiryMap[T]) GetByValue(val T) (key string, value T, ok bool) {
 	return "", *new(T), false
 }
 
+// GetAndRemove retrieves a value and removes it in one step, so concurrent
+// callers with the same key cannot both receive the value
+func (m *ExpiryMap[T]) GetAndRemove(key string) (T, bool) {
+	m.takeMu.Lock()
+	defer m.takeMu.Unlock()
+	value, ok := m.GetOk(key)
+	if ok {
+		m.store.Remove(key)
+	}
+	return value, ok
+}
+
 // Remove explicitly removes a key
 func (m *ExpiryMap[T]) Remove(key string) {
 	m.store.Remove(key)
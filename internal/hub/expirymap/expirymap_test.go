@@ -4,6 +4,8 @@
 package expirymap
 
 import (
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -225,6 +227,48 @@ func TestExpiryMap_Concurrent(t *testing.T) {
 	assert.True(t, em.Has("key"))
 }
 
+func TestExpiryMap_GetAndRemove(t *testing.T) {
+	em := New[string](time.Hour)
+
+	em.Set("key1", "value1", time.Hour)
+	value, ok := em.GetAndRemove("key1")
+	assert.True(t, ok)
+	assert.Equal(t, "value1", value)
+	assert.False(t, em.Has("key1"))
+
+	// Second take of the same key fails
+	value, ok = em.GetAndRemove("key1")
+	assert.False(t, ok)
+	assert.Equal(t, "", value)
+
+	// Expired entries are not returned
+	em.Set("expired", "value", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	_, ok = em.GetAndRemove("expired")
+	assert.False(t, ok)
+}
+
+func TestExpiryMap_GetAndRemove_Concurrent(t *testing.T) {
+	em := New[int](time.Hour)
+	em.Set("key", 1, time.Hour)
+
+	var taken atomic.Int32
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok := em.GetAndRemove("key"); ok {
+				taken.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Only one caller should receive the value
+	assert.Equal(t, int32(1), taken.Load())
+}
+
 func TestExpiryMap_GetByValue(t *testing.T) {
 	em := New[string](time.Hour)
 
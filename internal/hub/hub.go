@@ -9,12 +9,17 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/henrygd/beszel"
 	"github.com/henrygd/beszel/internal/alerts"
+	"github.com/henrygd/beszel/internal/entities/container"
+	"github.com/henrygd/beszel/internal/entities/systemd"
 	"github.com/henrygd/beszel/internal/hub/config"
+	"github.com/henrygd/beszel/internal/hub/expirymap"
 	"github.com/henrygd/beszel/internal/hub/systems"
 	"github.com/henrygd/beszel/internal/records"
 	"github.com/henrygd/beszel/internal/users"
@@ -36,6 +41,8 @@ type Hub struct {
 	pubKey string
 	signer ssh.Signer
 	appURL string
+	// serviceActionTokens stores confirmation tokens for stop / disable actions
+	serviceActionTokens serviceActionTokens
 }
 
 // NewHub creates a new Hub instance with default configuration
@@ -273,12 +280,19 @@ func (h *Hub) registerApiRoutes(se *core.ServeEvent) error {
 	apiAuth.POST("/smart/refresh", h.refreshSmartData)
 	// get systemd service details
 	apiAuth.GET("/systemd/info", h.getSystemdInfo)
+	// inspect / reset the agent WebSocket connection
+	apiAuth.GET("/agent-connection", h.getAgentConnection)
+	apiAuth.POST("/agent-connection/reconnect", h.reconnectAgent)
+	// start / stop / restart / enable / disable a systemd service
+	apiAuth.POST("/systemd/action", h.systemdServiceAction)
 	// /containers routes
 	if enabled, _ := GetEnv("CONTAINER_DETAILS"); enabled != "false" {
 		// get container logs
 		apiAuth.GET("/containers/logs", h.getContainerLogs)
 		// get container info
 		apiAuth.GET("/containers/info", h.getContainerInfo)
+		// start / stop / restart a container (allowed actions are returned by /containers/info)
+		apiAuth.POST("/containers/action", h.containerAction)
 	}
 	return nil
 }
@@ -411,6 +425,55 @@ func (h *Hub) getContainerInfo(e *core.RequestEvent) error {
 	}, "info")
 }
 
+// containerAction handles POST /api/beszel/containers/action requests.
+// The user must be allowed to update the system, and the agent must allow
+// the action for the container in CONTAINER_ACTIONS.
+func (h *Hub) containerAction(e *core.RequestEvent) error {
+	query := e.Request.URL.Query()
+	systemID := query.Get("system")
+	containerID := query.Get("container")
+	action := query.Get("action")
+
+	if systemID == "" || containerID == "" || action == "" {
+		return e.JSON(http.StatusBadRequest, map[string]string{"error": "system, container, and action parameters are required"})
+	}
+	if !slices.Contains(container.Actions, action) {
+		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid action"})
+	}
+
+	systemRecord, err := h.FindRecordById("systems", systemID)
+	if err != nil {
+		return e.JSON(http.StatusNotFound, map[string]string{"error": "system not found"})
+	}
+	info, err := e.RequestInfo()
+	if err != nil {
+		return err
+	}
+	if canUpdate, _ := h.CanAccessRecord(systemRecord, info, systemRecord.Collection().UpdateRule); !canUpdate {
+		return e.ForbiddenError("", nil)
+	}
+
+	system, err := h.sm.GetSystem(systemID)
+	if err != nil {
+		return e.JSON(http.StatusNotFound, map[string]string{"error": "system not found"})
+	}
+
+	status, err := system.PerformContainerActionOnAgent(containerID, action)
+	h.Logger().Info("Container action",
+		"system", systemRecord.GetString("name"),
+		"container", containerID,
+		"action", action,
+		"user", e.Auth.Id,
+		"status", status,
+		"err", err,
+	)
+	if err != nil {
+		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return e.JSON(http.StatusOK, map[string]string{"status": status})
+}
+
 // getSystemdInfo handles GET /api/beszel/systemd/info requests
 func (h *Hub) getSystemdInfo(e *core.RequestEvent) error {
 	query := e.Request.URL.Query()
@@ -432,6 +495,206 @@ func (h *Hub) getSystemdInfo(e *core.RequestEvent) error {
 	return e.JSON(http.StatusOK, map[string]any{"details": details})
 }
 
+// getAgentConnection handles GET /api/beszel/agent-connection requests.
+// Returns the transport used to reach the agent and WebSocket connection diagnostics.
+func (h *Hub) getAgentConnection(e *core.RequestEvent) error {
+	systemID := e.Request.URL.Query().Get("system")
+	if systemID == "" {
+		return e.JSON(http.StatusBadRequest, map[string]string{"error": "system parameter is required"})
+	}
+	systemRecord, err := h.FindRecordById("systems", systemID)
+	if err != nil {
+		return e.JSON(http.StatusNotFound, map[string]string{"error": "system not found"})
+	}
+	info, err := e.RequestInfo()
+	if err != nil {
+		return err
+	}
+	if canView, _ := h.CanAccessRecord(systemRecord, info, systemRecord.Collection().ViewRule); !canView {
+		return e.ForbiddenError("", nil)
+	}
+
+	system, err := h.sm.GetSystem(systemID)
+	if err != nil {
+		return e.JSON(http.StatusNotFound, map[string]string{"error": "system not found"})
+	}
+	response := map[string]any{"transport": system.Transport()}
+	// read once, the updater may clear WsConn concurrently
+	if wsConn := system.WsConn; wsConn != nil {
+		response["websocket"] = wsConn.Info()
+	}
+	return e.JSON(http.StatusOK, response)
+}
+
+// reconnectAgent handles POST /api/beszel/agent-connection/reconnect requests.
+// Closes the agent's WebSocket connection so the agent establishes a new one.
+func (h *Hub) reconnectAgent(e *core.RequestEvent) error {
+	systemID := e.Request.URL.Query().Get("system")
+	if systemID == "" {
+		return e.JSON(http.StatusBadRequest, map[string]string{"error": "system parameter is required"})
+	}
+	systemRecord, err := h.FindRecordById("systems", systemID)
+	if err != nil {
+		return e.JSON(http.StatusNotFound, map[string]string{"error": "system not found"})
+	}
+	info, err := e.RequestInfo()
+	if err != nil {
+		return err
+	}
+	if canUpdate, _ := h.CanAccessRecord(systemRecord, info, systemRecord.Collection().UpdateRule); !canUpdate {
+		return e.ForbiddenError("", nil)
+	}
+
+	system, err := h.sm.GetSystem(systemID)
+	if err != nil {
+		return e.JSON(http.StatusNotFound, map[string]string{"error": "system not found"})
+	}
+	// read once, the updater may clear WsConn concurrently
+	wsConn := system.WsConn
+	if wsConn == nil || !wsConn.IsConnected() {
+		return e.JSON(http.StatusBadRequest, map[string]string{"error": "agent is not connected via WebSocket"})
+	}
+	h.Logger().Info("Agent reconnect requested", "system", systemRecord.GetString("name"), "user", e.Auth.Id)
+	wsConn.Close([]byte("reconnect requested"))
+	return e.JSON(http.StatusOK, map[string]bool{"success": true})
+}
+
+// serviceActionConfirmTTL is how long a confirmation token for a stop / disable action is valid
+const serviceActionConfirmTTL = 2 * time.Minute
+
+// serviceActionImpactKeys are the service details returned as the impact summary of a stop / disable action
+var serviceActionImpactKeys = []string{"ActiveState", "SubState", "RequiredBy", "BoundBy", "ConsistsOf", "WantedBy", "TasksCurrent"}
+
+// pendingServiceAction is a stop / disable action waiting to be confirmed with a token
+type pendingServiceAction struct {
+	userID   string
+	systemID string
+	service  string
+	action   string
+}
+
+type serviceActionTokens struct {
+	store *expirymap.ExpiryMap[pendingServiceAction]
+	once  sync.Once
+}
+
+// GetMap returns the expirymap, creating it if necessary.
+func (st *serviceActionTokens) GetMap() *expirymap.ExpiryMap[pendingServiceAction] {
+	st.once.Do(func() {
+		st.store = expirymap.New[pendingServiceAction](time.Minute)
+	})
+	return st.store
+}
+
+// issue stores a pending action and returns its confirmation token
+func (st *serviceActionTokens) issue(pending pendingServiceAction) string {
+	token := uuid.New().String()
+	st.GetMap().Set(token, pending, serviceActionConfirmTTL)
+	return token
+}
+
+// confirm consumes the token and reports whether it was issued for the pending action.
+// Tokens are single use and bound to the user, system, service, and action.
+func (st *serviceActionTokens) confirm(token string, pending pendingServiceAction) bool {
+	confirmed, ok := st.GetMap().GetAndRemove(token)
+	return ok && confirmed == pending
+}
+
+// systemdServiceAction handles POST /api/beszel/systemd/action requests.
+// The user must be allowed to update the system, and the agent must allow
+// the action for the service in SERVICE_ACTIONS. Stop and disable are two-step:
+// a request without a token returns a confirmation token and impact summary,
+// and the action only runs when the token is sent back.
+func (h *Hub) systemdServiceAction(e *core.RequestEvent) error {
+	query := e.Request.URL.Query()
+	systemID := query.Get("system")
+	serviceName := query.Get("service")
+	action := query.Get("action")
+
+	if systemID == "" || serviceName == "" || action == "" {
+		return e.JSON(http.StatusBadRequest, map[string]string{"error": "system, service, and action parameters are required"})
+	}
+	if !slices.Contains(systemd.ServiceActions, action) {
+		return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid action"})
+	}
+
+	systemRecord, err := h.FindRecordById("systems", systemID)
+	if err != nil {
+		return e.JSON(http.StatusNotFound, map[string]string{"error": "system not found"})
+	}
+	info, err := e.RequestInfo()
+	if err != nil {
+		return err
+	}
+	if canUpdate, _ := h.CanAccessRecord(systemRecord, info, systemRecord.Collection().UpdateRule); !canUpdate {
+		return e.ForbiddenError("", nil)
+	}
+
+	system, err := h.sm.GetSystem(systemID)
+	if err != nil {
+		return e.JSON(http.StatusNotFound, map[string]string{"error": "system not found"})
+	}
+
+	if action == systemd.ActionStop || action == systemd.ActionDisable {
+		pending := pendingServiceAction{userID: e.Auth.Id, systemID: systemID, service: serviceName, action: action}
+		token := query.Get("token")
+		if token == "" {
+			details, err := system.FetchSystemdInfoFromAgent(serviceName)
+			if err != nil {
+				return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			}
+			return h.requestServiceActionConfirmation(e, pending, details)
+		}
+		if !h.serviceActionTokens.confirm(token, pending) {
+			return e.JSON(http.StatusBadRequest, map[string]string{"error": "invalid or expired confirmation token"})
+		}
+	}
+
+	result, err := system.PerformSystemdActionOnAgent(serviceName, action)
+	h.Logger().Info("Systemd service action",
+		"system", systemRecord.GetString("name"),
+		"service", serviceName,
+		"action", action,
+		"user", e.Auth.Id,
+		"result", result,
+		"err", err,
+	)
+	if err != nil {
+		return e.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return e.JSON(http.StatusOK, map[string]string{"result": result})
+}
+
+// requestServiceActionConfirmation stores a pending stop / disable action and responds
+// with its confirmation token and an impact summary taken from the service details.
+// No token is issued if the agent's SERVICE_ACTIONS does not allow the action.
+func (h *Hub) requestServiceActionConfirmation(e *core.RequestEvent, pending pendingServiceAction, details systemd.ServiceDetails) error {
+	if !serviceActionAllowed(details, pending.action) {
+		return e.JSON(http.StatusForbidden, map[string]string{"error": "action not allowed by the agent's SERVICE_ACTIONS"})
+	}
+	impact := make(map[string]any)
+	for _, key := range serviceActionImpactKeys {
+		if value, ok := details[key]; ok {
+			impact[key] = value
+		}
+	}
+	token := h.serviceActionTokens.issue(pending)
+	return e.JSON(http.StatusAccepted, map[string]any{"token": token, "impact": impact})
+}
+
+// serviceActionAllowed reports whether the AllowedActions in the agent's service
+// details include the action. Decoded details hold the list as []any.
+func serviceActionAllowed(details systemd.ServiceDetails, action string) bool {
+	switch allowed := details["AllowedActions"].(type) {
+	case []string:
+		return slices.Contains(allowed, action)
+	case []any:
+		return slices.Contains(allowed, any(action))
+	}
+	return false
+}
+
 // refreshSmartData handles POST /api/beszel/smart/refresh requests
 // Fetches fresh SMART data from the agent and updates the collection
 func (h *Hub) refreshSmartData(e *core.RequestEvent) error {
@@ -293,13 +293,25 @@ func TestApiRoutesAuthentication(t *testing.T) {
 	userToken, err := user.NewAuthToken()
 	require.NoError(t, err, "Failed to create auth token")
 
+	readOnlyUser, err := beszelTests.CreateRecord(hub, "users", map[string]any{
+		"email":    "readonly@example.com",
+		"password": "password123",
+		"role":     "readonly",
+	})
+	require.NoError(t, err, "Failed to create readonly user")
+	readOnlyUserToken, err := readOnlyUser.NewAuthToken()
+	require.NoError(t, err, "Failed to create readonly auth token")
+
 	// Create test system for user-alerts endpoints
 	system, err := beszelTests.CreateRecord(hub, "systems", map[string]any{
 		"name":  "test-system",
-		"users": []string{user.Id},
+		"users": []string{user.Id, readOnlyUser.Id},
 		"host":  "127.0.0.1",
 	})
 	require.NoError(t, err, "Failed to create test system")
+	// the system manager loads existing systems in the background, so register it directly
+	// for routes that look up the system (action tokens, agent connection)
+	require.NoError(t, hub.GetSystemManager().AddRecord(system, nil), "Failed to add test system to manager")
 
 	testAppFactory := func(t testing.TB) *pbTests.TestApp {
 		return hub.TestApp
@@ -502,6 +514,225 @@ func TestApiRoutesAuthentication(t *testing.T) {
 			TestAppFactory:  testAppFactory,
 		},
 
+		{
+			Name:            "POST /systemd/action - no auth should fail",
+			Method:          http.MethodPost,
+			URL:             "/api/beszel/systemd/action?system=" + system.Id + "&service=nginx&action=restart",
+			ExpectedStatus:  401,
+			ExpectedContent: []string{"requires valid"},
+			TestAppFactory:  testAppFactory,
+		},
+		{
+			Name:   "POST /systemd/action - with auth but missing service param should fail",
+			Method: http.MethodPost,
+			URL:    "/api/beszel/systemd/action?system=" + system.Id + "&action=restart",
+			Headers: map[string]string{
+				"Authorization": userToken,
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{"system, service, and action parameters are required"},
+			TestAppFactory:  testAppFactory,
+		},
+		{
+			Name:   "POST /systemd/action - with auth but invalid action should fail",
+			Method: http.MethodPost,
+			URL:    "/api/beszel/systemd/action?system=" + system.Id + "&service=nginx&action=reload",
+			Headers: map[string]string{
+				"Authorization": userToken,
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{"invalid action"},
+			TestAppFactory:  testAppFactory,
+		},
+		{
+			Name:   "POST /systemd/action - readonly user should be forbidden",
+			Method: http.MethodPost,
+			URL:    "/api/beszel/systemd/action?system=" + system.Id + "&service=nginx&action=restart",
+			Headers: map[string]string{
+				"Authorization": readOnlyUserToken,
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{"not allowed"},
+			TestAppFactory:  testAppFactory,
+		},
+		{
+			Name:   "POST /systemd/action - with auth but invalid system should fail",
+			Method: http.MethodPost,
+			URL:    "/api/beszel/systemd/action?system=invalid-system&service=nginx&action=restart",
+			Headers: map[string]string{
+				"Authorization": userToken,
+			},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{"system not found"},
+			TestAppFactory:  testAppFactory,
+		},
+		{
+			Name:   "POST /systemd/action - stop with invalid confirmation token should fail",
+			Method: http.MethodPost,
+			URL:    "/api/beszel/systemd/action?system=" + system.Id + "&service=nginx&action=stop&token=invalid-token",
+			Headers: map[string]string{
+				"Authorization": userToken,
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{"invalid or expired confirmation token"},
+			TestAppFactory:  testAppFactory,
+		},
+		{
+			Name:            "POST /containers/action - no auth should fail",
+			Method:          http.MethodPost,
+			URL:             "/api/beszel/containers/action?system=" + system.Id + "&container=abc123&action=restart",
+			ExpectedStatus:  401,
+			ExpectedContent: []string{"requires valid"},
+			TestAppFactory:  testAppFactory,
+		},
+		{
+			Name:   "POST /containers/action - with auth but missing container param should fail",
+			Method: http.MethodPost,
+			URL:    "/api/beszel/containers/action?system=" + system.Id + "&action=restart",
+			Headers: map[string]string{
+				"Authorization": userToken,
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{"system, container, and action parameters are required"},
+			TestAppFactory:  testAppFactory,
+		},
+		{
+			Name:   "POST /containers/action - with auth but invalid action should fail",
+			Method: http.MethodPost,
+			URL:    "/api/beszel/containers/action?system=" + system.Id + "&container=abc123&action=kill",
+			Headers: map[string]string{
+				"Authorization": userToken,
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{"invalid action"},
+			TestAppFactory:  testAppFactory,
+		},
+		{
+			Name:   "POST /containers/action - readonly user should be forbidden",
+			Method: http.MethodPost,
+			URL:    "/api/beszel/containers/action?system=" + system.Id + "&container=abc123&action=restart",
+			Headers: map[string]string{
+				"Authorization": readOnlyUserToken,
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{"not allowed"},
+			TestAppFactory:  testAppFactory,
+		},
+		{
+			Name:   "POST /containers/action - with auth but invalid system should fail",
+			Method: http.MethodPost,
+			URL:    "/api/beszel/containers/action?system=invalid-system&container=abc123&action=restart",
+			Headers: map[string]string{
+				"Authorization": userToken,
+			},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{"system not found"},
+			TestAppFactory:  testAppFactory,
+		},
+		{
+			Name:            "GET /agent-connection - no auth should fail",
+			Method:          http.MethodGet,
+			URL:             "/api/beszel/agent-connection?system=" + system.Id + "",
+			ExpectedStatus:  401,
+			ExpectedContent: []string{"requires valid"},
+			TestAppFactory:  testAppFactory,
+		},
+		{
+			Name:   "GET /agent-connection - with auth but missing system param should fail",
+			Method: http.MethodGet,
+			URL:    "/api/beszel/agent-connection",
+			Headers: map[string]string{
+				"Authorization": userToken,
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{"system parameter is required"},
+			TestAppFactory:  testAppFactory,
+		},
+		{
+			Name:   "GET /agent-connection - user without access to system should be forbidden",
+			Method: http.MethodGet,
+			URL:    "/api/beszel/agent-connection?system=" + system.Id + "",
+			Headers: map[string]string{
+				"Authorization": adminUserToken,
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{"not allowed"},
+			TestAppFactory:  testAppFactory,
+		},
+		{
+			Name:   "GET /agent-connection - with auth but invalid system should fail",
+			Method: http.MethodGet,
+			URL:    "/api/beszel/agent-connection?system=invalid-system",
+			Headers: map[string]string{
+				"Authorization": userToken,
+			},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{"system not found"},
+			TestAppFactory:  testAppFactory,
+		},
+		{
+			Name:   "GET /agent-connection - readonly user with access should succeed",
+			Method: http.MethodGet,
+			URL:    "/api/beszel/agent-connection?system=" + system.Id + "",
+			Headers: map[string]string{
+				"Authorization": readOnlyUserToken,
+			},
+			ExpectedStatus:  200,
+			ExpectedContent: []string{"\"transport\":"},
+			TestAppFactory:  testAppFactory,
+		},
+		{
+			Name:            "POST /agent-connection/reconnect - no auth should fail",
+			Method:          http.MethodPost,
+			URL:             "/api/beszel/agent-connection/reconnect?system=" + system.Id + "",
+			ExpectedStatus:  401,
+			ExpectedContent: []string{"requires valid"},
+			TestAppFactory:  testAppFactory,
+		},
+		{
+			Name:   "POST /agent-connection/reconnect - with auth but missing system param should fail",
+			Method: http.MethodPost,
+			URL:    "/api/beszel/agent-connection/reconnect",
+			Headers: map[string]string{
+				"Authorization": userToken,
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{"system parameter is required"},
+			TestAppFactory:  testAppFactory,
+		},
+		{
+			Name:   "POST /agent-connection/reconnect - readonly user should be forbidden",
+			Method: http.MethodPost,
+			URL:    "/api/beszel/agent-connection/reconnect?system=" + system.Id + "",
+			Headers: map[string]string{
+				"Authorization": readOnlyUserToken,
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{"not allowed"},
+			TestAppFactory:  testAppFactory,
+		},
+		{
+			Name:   "POST /agent-connection/reconnect - with auth but invalid system should fail",
+			Method: http.MethodPost,
+			URL:    "/api/beszel/agent-connection/reconnect?system=invalid-system",
+			Headers: map[string]string{
+				"Authorization": userToken,
+			},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{"system not found"},
+			TestAppFactory:  testAppFactory,
+		},
+		{
+			Name:   "POST /agent-connection/reconnect - agent without WebSocket should fail",
+			Method: http.MethodPost,
+			URL:    "/api/beszel/agent-connection/reconnect?system=" + system.Id + "",
+			Headers: map[string]string{
+				"Authorization": userToken,
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{"agent is not connected via WebSocket"},
+			TestAppFactory:  testAppFactory,
+		},
 		// Auth Optional Routes - Should work without authentication
 		{
 			Name:            "GET /getkey - no auth should fail",
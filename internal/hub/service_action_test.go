@@ -0,0 +1,119 @@
+//go:build testing
+// +build testing
+
+package hub
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/henrygd/beszel/internal/entities/systemd"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceActionConfirmation(t *testing.T) {
+	pending := pendingServiceAction{userID: "user1", systemID: "system1", service: "nginx.service", action: systemd.ActionStop}
+
+	t.Run("no token returns 202 with token and impact", func(t *testing.T) {
+		h := &Hub{}
+		rec := httptest.NewRecorder()
+		e := &core.RequestEvent{}
+		e.Request = httptest.NewRequest(http.MethodPost, "/api/beszel/systemd/action", nil)
+		e.Response = rec
+
+		details := systemd.ServiceDetails{"ActiveState": "active", "WantedBy": []string{"multi-user.target"}, "Description": "nginx", "AllowedActions": []string{"stop", "restart"}}
+		require.NoError(t, h.requestServiceActionConfirmation(e, pending, details))
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+
+		var body struct {
+			Token  string         `json:"token"`
+			Impact map[string]any `json:"impact"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.NotEmpty(t, body.Token)
+		assert.Equal(t, "active", body.Impact["ActiveState"])
+		assert.Contains(t, body.Impact, "WantedBy")
+		assert.NotContains(t, body.Impact, "Description")
+		assert.NotContains(t, body.Impact, "AllowedActions")
+
+		// the returned token confirms the action once
+		assert.True(t, h.serviceActionTokens.confirm(body.Token, pending))
+		assert.False(t, h.serviceActionTokens.confirm(body.Token, pending))
+	})
+
+	t.Run("action not allowed by the agent returns 403 without token", func(t *testing.T) {
+		for _, details := range []systemd.ServiceDetails{
+			{"ActiveState": "active"},
+			{"ActiveState": "active", "AllowedActions": []any{"restart"}},
+		} {
+			h := &Hub{}
+			rec := httptest.NewRecorder()
+			e := &core.RequestEvent{}
+			e.Request = httptest.NewRequest(http.MethodPost, "/api/beszel/systemd/action", nil)
+			e.Response = rec
+
+			require.NoError(t, h.requestServiceActionConfirmation(e, pending, details))
+			assert.Equal(t, http.StatusForbidden, rec.Code)
+			assert.NotContains(t, rec.Body.String(), "token")
+		}
+	})
+
+	t.Run("reused token is rejected", func(t *testing.T) {
+		h := &Hub{}
+		token := h.serviceActionTokens.issue(pending)
+		assert.True(t, h.serviceActionTokens.confirm(token, pending))
+		assert.False(t, h.serviceActionTokens.confirm(token, pending))
+	})
+
+	t.Run("unknown token is rejected", func(t *testing.T) {
+		h := &Hub{}
+		assert.False(t, h.serviceActionTokens.confirm("unknown", pending))
+	})
+
+	tests := []struct {
+		name   string
+		modify func(p *pendingServiceAction)
+	}{
+		{"other user", func(p *pendingServiceAction) { p.userID = "user2" }},
+		{"other system", func(p *pendingServiceAction) { p.systemID = "system2" }},
+		{"other service", func(p *pendingServiceAction) { p.service = "sshd.service" }},
+		{"other action", func(p *pendingServiceAction) { p.action = systemd.ActionDisable }},
+	}
+
+	for _, tt := range tests {
+		t.Run("token bound to "+tt.name+" is rejected", func(t *testing.T) {
+			h := &Hub{}
+			token := h.serviceActionTokens.issue(pending)
+			other := pending
+			tt.modify(&other)
+			assert.False(t, h.serviceActionTokens.confirm(token, other))
+			// a mismatched attempt still consumes the token
+			assert.False(t, h.serviceActionTokens.confirm(token, pending))
+		})
+	}
+}
+
+func TestServiceActionAllowed(t *testing.T) {
+	tests := []struct {
+		name     string
+		details  systemd.ServiceDetails
+		expected bool
+	}{
+		{"no allowed actions", systemd.ServiceDetails{}, false},
+		{"string slice", systemd.ServiceDetails{"AllowedActions": []string{"stop", "restart"}}, true},
+		{"decoded slice", systemd.ServiceDetails{"AllowedActions": []any{"stop"}}, true},
+		{"action missing", systemd.ServiceDetails{"AllowedActions": []any{"restart"}}, false},
+		{"unexpected type", systemd.ServiceDetails{"AllowedActions": "stop"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, serviceActionAllowed(tt.details, systemd.ActionStop))
+		})
+	}
+}
@@ -379,10 +379,32 @@ func (sys *System) request(ctx context.Context, action common.WebSocketAction, r
 	}
 
 	// Fall back to SSH if WebSocket fails
+	return sys.requestViaSSH(ctx, action, req, dest, 1)
+}
+
+// requestOnce sends a request that must not be repeated, such as a service or
+// container action. Once the request has been sent over WebSocket it does not
+// fall back to SSH, because the agent may already have run it, and SSH is not retried.
+func (sys *System) requestOnce(ctx context.Context, action common.WebSocketAction, req any, dest any) error {
+	if sys.WsConn != nil && sys.WsConn.IsConnected() {
+		wsTransport := transport.NewWebSocketTransport(sys.WsConn)
+		err := wsTransport.Request(ctx, action, req, dest)
+		if !errors.Is(err, transport.ErrWebSocketNotConnected) {
+			if shouldCloseWebSocket(err) {
+				sys.closeWebSocketConnection()
+			}
+			return err
+		}
+	}
+	return sys.requestViaSSH(ctx, action, req, dest, 0)
+}
+
+// requestViaSSH sends a request to the agent over SSH, retrying connection errors.
+func (sys *System) requestViaSSH(ctx context.Context, action common.WebSocketAction, req any, dest any, retries int) error {
 	if err := sys.ensureSSHTransport(); err != nil {
 		return err
 	}
-	err := sys.sshTransport.RequestWithRetry(ctx, action, req, dest, 1)
+	err := sys.sshTransport.RequestWithRetry(ctx, action, req, dest, retries)
 	// Keep legacy SSH client/version fields in sync for other code paths.
 	if sys.sshTransport != nil {
 		sys.client = sys.sshTransport.GetClient()
@@ -486,15 +508,36 @@ func (sys *System) FetchContainerLogsFromAgent(containerID string) (string, erro
 	return result, err
 }
 
-// FetchSystemdInfoFromAgent fetches detailed systemd service information from the agent
+// FetchSystemdInfoFromAgent fetches detailed systemd service information from the agent.
+// The timeout allows for the agent's D-Bus property reads plus its journal lookup.
 func (sys *System) FetchSystemdInfoFromAgent(serviceName string) (systemd.ServiceDetails, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	var result systemd.ServiceDetails
 	err := sys.request(ctx, common.GetSystemdInfo, common.SystemdInfoRequest{ServiceName: serviceName}, &result)
 	return result, err
 }
 
+// PerformSystemdActionOnAgent asks the agent to perform an action on a systemd service
+// and returns the systemd job result.
+func (sys *System) PerformSystemdActionOnAgent(serviceName, action string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 35*time.Second)
+	defer cancel()
+	var result common.SystemdActionResponse
+	err := sys.requestOnce(ctx, common.SystemdServiceAction, common.SystemdActionRequest{ServiceName: serviceName, Action: action}, &result)
+	return result.Result, err
+}
+
+// PerformContainerActionOnAgent asks the agent to start, stop or restart a container
+// and returns the container state after the action.
+func (sys *System) PerformContainerActionOnAgent(containerID, action string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 35*time.Second)
+	defer cancel()
+	var result common.ContainerActionResponse
+	err := sys.requestOnce(ctx, common.ContainerAction, common.ContainerActionRequest{ContainerID: containerID, Action: action}, &result)
+	return result.Status, err
+}
+
 // FetchSmartDataFromAgent fetches SMART data from the agent
 func (sys *System) FetchSmartDataFromAgent() (map[string]smart.SmartData, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
@@ -683,6 +726,18 @@ func (sys *System) closeWebSocketConnection() {
 	}
 }
 
+// Transport returns how the hub currently reaches the agent: "websocket", "ssh", or "" if neither is connected.
+func (sys *System) Transport() string {
+	// read once, the updater may clear WsConn concurrently
+	if wsConn := sys.WsConn; wsConn != nil && wsConn.IsConnected() {
+		return "websocket"
+	}
+	if sys.client != nil {
+		return "ssh"
+	}
+	return ""
+}
+
 // extractAgentVersion extracts the beszel version from SSH server version string
 func extractAgentVersion(versionString string) (semver.Version, error) {
 	_, after, _ := strings.Cut(versionString, "_")
@@ -30,6 +30,7 @@ type RequestManager struct {
 	conn        *gws.Conn
 	pendingReqs map[RequestID]*PendingRequest
 	nextID      atomic.Uint32
+	lastRtt     atomic.Int64 // Round trip time of the last answered request in nanoseconds
 }
 
 // NewRequestManager creates a new request manager for a WebSocket connection
@@ -128,6 +129,7 @@ func (rm *RequestManager) handleResponse(message *gws.Message) {
 	select {
 	case req.ResponseCh <- message:
 		// Message successfully delivered - the receiver will close it
+		rm.lastRtt.Store(int64(time.Since(req.CreatedAt)))
 		rm.deleteRequest(reqID)
 	case <-req.Context.Done():
 		// Request was cancelled/timed out - close the message
@@ -186,6 +188,13 @@ func (rm *RequestManager) deleteRequest(reqID RequestID) {
 	delete(rm.pendingReqs, reqID)
 }
 
+// GetPendingCount returns the number of pending requests (for monitoring)
+func (rm *RequestManager) GetPendingCount() int {
+	rm.RLock()
+	defer rm.RUnlock()
+	return len(rm.pendingReqs)
+}
+
 // Close shuts down the request manager
 func (rm *RequestManager) Close() {
 	rm.Lock()
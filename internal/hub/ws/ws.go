@@ -3,6 +3,8 @@ package ws
 import (
 	"context"
 	"errors"
+	"os"
+	"sync/atomic"
 	"time"
 	"weak"
 
@@ -30,6 +32,18 @@ type WsConn struct {
 	requestManager *RequestManager
 	DownChan       chan struct{}
 	agentVersion   semver.Version
+	connectedAt    time.Time
+	lastMessageAt  atomic.Int64 // Unix milliseconds of the last message from the agent
+}
+
+// ConnectionInfo contains diagnostics about an agent WebSocket connection.
+type ConnectionInfo struct {
+	Connected       bool      `json:"connected"`
+	ConnectedAt     time.Time `json:"connectedAt"`
+	LastMessageAt   time.Time `json:"lastMessageAt,omitzero"`
+	LastRttMs       float64   `json:"lastRttMs"`
+	PendingRequests int       `json:"pendingRequests"`
+	AgentVersion    string    `json:"agentVersion"`
 }
 
 // FingerprintRecord is fingerprints collection record data in the hub
@@ -43,15 +57,31 @@ type FingerprintRecord struct {
 var upgrader *gws.Upgrader
 
 // GetUpgrader returns a singleton WebSocket upgrader instance.
+// Agents that request permessage-deflate get it if WS_COMPRESSION is true.
 func GetUpgrader() *gws.Upgrader {
 	if upgrader != nil {
 		return upgrader
 	}
-	handler := &Handler{}
-	upgrader = gws.NewUpgrader(handler, &gws.ServerOption{})
+	upgrader = newUpgrader(compressionEnabled())
 	return upgrader
 }
 
+// newUpgrader creates a WebSocket upgrader, optionally allowing permessage-deflate.
+func newUpgrader(compression bool) *gws.Upgrader {
+	return gws.NewUpgrader(&Handler{}, &gws.ServerOption{
+		PermessageDeflate: gws.PermessageDeflate{Enabled: compression},
+	})
+}
+
+// compressionEnabled reports whether BESZEL_HUB_WS_COMPRESSION (or WS_COMPRESSION) is true.
+func compressionEnabled() bool {
+	value, exists := os.LookupEnv("BESZEL_HUB_WS_COMPRESSION")
+	if !exists {
+		value = os.Getenv("WS_COMPRESSION")
+	}
+	return value == "true"
+}
+
 // NewWsConnection creates a new WebSocket connection wrapper with agent version.
 func NewWsConnection(conn *gws.Conn, agentVersion semver.Version) *WsConn {
 	return &WsConn{
@@ -59,6 +89,7 @@ func NewWsConnection(conn *gws.Conn, agentVersion semver.Version) *WsConn {
 		requestManager: NewRequestManager(conn),
 		DownChan:       make(chan struct{}, 1),
 		agentVersion:   agentVersion,
+		connectedAt:    time.Now(),
 	}
 }
 
@@ -78,6 +109,7 @@ func (h *Handler) OnMessage(conn *gws.Conn, message *gws.Message) {
 		_ = conn.WriteClose(1000, nil)
 		return
 	}
+	wsConn.(*WsConn).lastMessageAt.Store(time.Now().UnixMilli())
 	wsConn.(*WsConn).requestManager.handleResponse(message)
 }
 
@@ -168,6 +200,23 @@ func (ws *WsConn) AgentVersion() semver.Version {
 	return ws.agentVersion
 }
 
+// Info returns diagnostics about the connection for troubleshooting.
+func (ws *WsConn) Info() ConnectionInfo {
+	info := ConnectionInfo{
+		Connected:    ws.IsConnected(),
+		ConnectedAt:  ws.connectedAt,
+		AgentVersion: ws.agentVersion.String(),
+	}
+	if lastMessageAt := ws.lastMessageAt.Load(); lastMessageAt > 0 {
+		info.LastMessageAt = time.UnixMilli(lastMessageAt)
+	}
+	if ws.requestManager != nil {
+		info.LastRttMs = float64(ws.requestManager.lastRtt.Load()) / float64(time.Millisecond)
+		info.PendingRequests = ws.requestManager.GetPendingCount()
+	}
+	return info
+}
+
 // SendRequest sends a request to the agent and returns a pending request handle.
 // This is used by the transport layer to send requests.
 func (ws *WsConn) SendRequest(ctx context.Context, action common.WebSocketAction, data any) (*PendingRequest, error) {
@@ -5,6 +5,10 @@ package ws
 
 import (
 	"crypto/ed25519"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +16,7 @@ import (
 	"github.com/henrygd/beszel/internal/common"
 
 	"github.com/fxamacker/cbor/v2"
+	"github.com/lxzan/gws"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/ssh"
@@ -34,6 +39,48 @@ func TestGetUpgrader(t *testing.T) {
 	assert.NotNil(t, upgrader1, "Upgrader should be configured")
 }
 
+// TestUpgraderCompression tests that permessage-deflate is negotiated only when WS_COMPRESSION is true
+func TestUpgraderCompression(t *testing.T) {
+	tests := []struct {
+		name              string
+		env               string
+		clientCompression bool
+		expectDeflate     bool
+	}{
+		{"enabled for client that requests it", "true", true, true},
+		{"enabled but client does not request it", "true", false, false},
+		{"disabled by default", "", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Unsetenv("WS_COMPRESSION")
+			t.Setenv("BESZEL_HUB_WS_COMPRESSION", tt.env)
+			upgrader = nil
+			defer func() { upgrader = nil }()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				conn, err := GetUpgrader().Upgrade(w, r)
+				if err != nil {
+					return
+				}
+				go conn.ReadLoop()
+			}))
+			defer server.Close()
+
+			client, resp, err := gws.NewClient(&gws.BuiltinEventHandler{}, &gws.ClientOption{
+				Addr:              "ws://" + strings.TrimPrefix(server.URL, "http://"),
+				PermessageDeflate: gws.PermessageDeflate{Enabled: tt.clientCompression},
+			})
+			require.NoError(t, err)
+			defer client.NetConn().Close()
+
+			extensions := resp.Header.Get("Sec-WebSocket-Extensions")
+			assert.Equal(t, tt.expectDeflate, strings.Contains(extensions, "permessage-deflate"))
+		})
+	}
+}
+
 // TestNewWsConnection tests WebSocket connection creation
 func TestNewWsConnection(t *testing.T) {
 	// We can't easily mock gws.Conn, so we'll pass nil and test the structure
@@ -63,6 +110,24 @@ func TestWsConn_Close(t *testing.T) {
 	}, "Should not panic when closing nil connection")
 }
 
+// TestWsConn_Info tests the connection diagnostics
+func TestWsConn_Info(t *testing.T) {
+	wsConn := NewWsConnection(nil, semver.MustParse("0.12.10"))
+
+	info := wsConn.Info()
+	assert.False(t, info.Connected, "Should not be connected when conn is nil")
+	assert.Equal(t, "0.12.10", info.AgentVersion)
+	assert.WithinDuration(t, time.Now(), info.ConnectedAt, time.Second)
+	assert.True(t, info.LastMessageAt.IsZero(), "No messages received yet")
+	assert.Zero(t, info.PendingRequests)
+
+	wsConn.lastMessageAt.Store(time.Now().UnixMilli())
+	wsConn.requestManager.lastRtt.Store(int64(25 * time.Millisecond))
+	info = wsConn.Info()
+	assert.WithinDuration(t, time.Now(), info.LastMessageAt, time.Second)
+	assert.Equal(t, 25.0, info.LastRttMs)
+}
+
 // TestWsConn_SendMessage_CBOR tests CBOR encoding in sendMessage
 func TestWsConn_SendMessage_CBOR(t *testing.T) {
 	wsConn := NewWsConnection(nil, semver.MustParse("0.12.10"))
@@ -16,7 +16,8 @@ import { useVirtualizer, type VirtualItem } from "@tanstack/react-virtual"
 import { memo, RefObject, useEffect, useRef, useState } from "react"
 import { Input } from "@/components/ui/input"
 import { TableBody, TableCell, TableHead, TableHeader, TableRow } from "@/components/ui/table"
-import { pb } from "@/lib/api"
+import { toast } from "@/components/ui/use-toast"
+import { isReadOnlyUser, pb } from "@/lib/api"
 import type { ContainerRecord } from "@/types"
 import { containerChartCols } from "@/components/containers-table/containers-table-columns"
 import { Card, CardDescription, CardHeader, CardTitle } from "@/components/ui/card"
@@ -274,7 +275,7 @@ async function getLogsHtml(container: ContainerRecord): Promise<string> {
 	}
 }
 
-async function getInfoHtml(container: ContainerRecord): Promise<string> {
+async function getInfoHtml(container: ContainerRecord): Promise<{ html: string; allowedActions: string[] }> {
 	try {
 		let [{ highlighter }, { info }] = await Promise.all([
 			import("@/lib/shiki"),
@@ -283,13 +284,21 @@ async function getInfoHtml(container: ContainerRecord): Promise<string> {
 				container: container.id,
 			}),
 		])
+		let allowedActions: string[] = []
 		try {
-			info = JSON.stringify(JSON.parse(info), null, 2)
+			const parsed = JSON.parse(info)
+			// actions allowed by the agent's CONTAINER_ACTIONS are not part of the inspect data
+			allowedActions = parsed.AllowedActions ?? []
+			delete parsed.AllowedActions
+			info = JSON.stringify(parsed, null, 2)
 		} catch (_) { }
-		return info ? highlighter.codeToHtml(info, { lang: "json", theme: syntaxTheme }) : t`No results.`
+		return {
+			html: info ? highlighter.codeToHtml(info, { lang: "json", theme: syntaxTheme }) : t`No results.`,
+			allowedActions,
+		}
 	} catch (error) {
 		console.error(error)
-		return ""
+		return { html: "", allowedActions: [] }
 	}
 }
 
@@ -310,6 +319,8 @@ function ContainerSheet({
 	const [logsFullscreenOpen, setLogsFullscreenOpen] = useState<boolean>(false)
 	const [infoFullscreenOpen, setInfoFullscreenOpen] = useState<boolean>(false)
 	const [isRefreshingLogs, setIsRefreshingLogs] = useState<boolean>(false)
+	const [allowedActions, setAllowedActions] = useState<string[]>([])
+	const [pendingAction, setPendingAction] = useState<string | null>(null)
 	const logsContainerRef = useRef<HTMLDivElement>(null)
 
 	function scrollLogsToBottom() {
@@ -338,14 +349,48 @@ function ContainerSheet({
 		}
 	}
 
+	const actionLabels: Record<string, string> = {
+		start: t`Start`,
+		stop: t`Stop`,
+		restart: t`Restart`,
+	}
+
+	const runAction = async (action: string) => {
+		setPendingAction(action)
+		try {
+			await pb.send<{ status: string }>("/api/beszel/containers/action", {
+				method: "POST",
+				query: {
+					system: container.system,
+					container: container.id,
+					action,
+				},
+			})
+			const [logsHtml, info] = await Promise.all([getLogsHtml(container), getInfoHtml(container)])
+			setLogsDisplay(logsHtml)
+			setInfoDisplay(info.html)
+			setTimeout(scrollLogsToBottom, 20)
+		} catch (err: any) {
+			toast({
+				title: t`Error`,
+				description: err?.message,
+				variant: "destructive",
+			})
+		} finally {
+			setPendingAction(null)
+		}
+	}
+
 	useEffect(() => {
 		setLogsDisplay("")
 		setInfoDisplay("")
+		setAllowedActions([])
 		if (!container) return
 			; (async () => {
-				const [logsHtml, infoHtml] = await Promise.all([getLogsHtml(container), getInfoHtml(container)])
+				const [logsHtml, info] = await Promise.all([getLogsHtml(container), getInfoHtml(container)])
 				setLogsDisplay(logsHtml)
-				setInfoDisplay(infoHtml)
+				setInfoDisplay(info.html)
+				setAllowedActions(isReadOnlyUser() ? [] : info.allowedActions)
 				setTimeout(scrollLogsToBottom, 20)
 			})()
 	}, [container])
@@ -385,6 +430,22 @@ function ContainerSheet({
 						</SheetDescription>
 					</SheetHeader>
 					<div className="px-3 pb-3 -mt-4 flex flex-col gap-3 h-full items-start">
+						{allowedActions.length > 0 && (
+							<div className="flex flex-wrap gap-2">
+								{allowedActions.map((action) => (
+									<Button
+										key={action}
+										variant="outline"
+										size="sm"
+										disabled={pendingAction !== null}
+										onClick={() => runAction(action)}
+									>
+										{pendingAction === action && <LoaderCircleIcon className="me-2 size-4 animate-spin" />}
+										{actionLabels[action] ?? action}
+									</Button>
+								))}
+							</div>
+						)}
 						<div className="flex items-center w-full">
 							<h3>{t`Logs`}</h3>
 							<Button
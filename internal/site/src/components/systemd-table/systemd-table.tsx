@@ -18,11 +18,23 @@ import { listenKeys } from "nanostores"
 import { memo, type ReactNode, useEffect, useMemo, useRef, useState } from "react"
 import { getStatusColor, systemdTableCols } from "@/components/systemd-table/systemd-table-columns"
 import { Alert, AlertDescription, AlertTitle } from "@/components/ui/alert"
+import {
+	AlertDialog,
+	AlertDialogAction,
+	AlertDialogCancel,
+	AlertDialogContent,
+	AlertDialogDescription,
+	AlertDialogFooter,
+	AlertDialogHeader,
+	AlertDialogTitle,
+} from "@/components/ui/alert-dialog"
+import { Button, buttonVariants } from "@/components/ui/button"
 import { Card, CardDescription, CardHeader, CardTitle } from "@/components/ui/card"
 import { Input } from "@/components/ui/input"
 import { Sheet, SheetContent, SheetHeader, SheetTitle } from "@/components/ui/sheet"
 import { TableBody, TableCell, TableHead, TableHeader, TableRow } from "@/components/ui/table"
-import { pb } from "@/lib/api"
+import { toast } from "@/components/ui/use-toast"
+import { isReadOnlyUser, pb } from "@/lib/api"
 import { ServiceStatus, ServiceStatusLabels, type ServiceSubState, ServiceSubStateLabels } from "@/lib/enums"
 import { $allSystemsById } from "@/lib/stores"
 import { cn, decimalString, formatBytes, useBrowserStorage } from "@/lib/utils"
@@ -255,6 +267,18 @@ const AllSystemdTable = memo(function AllSystemdTable({
 	)
 })
 
+// Pending stop / disable action returned by the hub for confirmation
+interface ServiceActionConfirmation {
+	action: string
+	token: string
+	impact: {
+		RequiredBy?: string[]
+		BoundBy?: string[]
+		WantedBy?: string[]
+		TasksCurrent?: number | null
+	}
+}
+
 function SystemdSheet({
 	sheetOpen,
 	setSheetOpen,
@@ -270,6 +294,9 @@ function SystemdSheet({
 	const [details, setDetails] = useState<SystemdServiceDetails | null>(null)
 	const [isLoading, setIsLoading] = useState(false)
 	const [error, setError] = useState<string | null>(null)
+	const [pendingAction, setPendingAction] = useState<string | null>(null)
+	const [refreshKey, setRefreshKey] = useState(0)
+	const [confirmation, setConfirmation] = useState<ServiceActionConfirmation | null>(null)
 
 	useEffect(() => {
 		if (!sheetOpen || !service) {
@@ -311,10 +338,52 @@ function SystemdSheet({
 		return () => {
 			cancelled = true
 		}
-	}, [sheetOpen, service, systemId])
+	}, [sheetOpen, service, systemId, refreshKey])
 
 	if (!service) return null
 
+	const actionLabels: Record<string, string> = {
+		start: t`Start`,
+		stop: t`Stop`,
+		restart: t`Restart`,
+		enable: t`Enable`,
+		disable: t`Disable`,
+	}
+
+	const runAction = async (action: string, token?: string) => {
+		setPendingAction(action)
+		try {
+			const res = await pb.send<{ result?: string; token?: string; impact?: ServiceActionConfirmation["impact"] }>(
+				"/api/beszel/systemd/action",
+				{
+					method: "POST",
+					query: {
+						system: systemId,
+						service: service.name,
+						action,
+						token,
+					},
+				}
+			)
+			// stop and disable must be confirmed with the returned token
+			if (res.token) {
+				setConfirmation({ action, token: res.token, impact: res.impact ?? {} })
+				return
+			}
+			setRefreshKey((key) => key + 1)
+		} catch (err: any) {
+			toast({
+				title: t`Error`,
+				description: err?.message,
+				variant: "destructive",
+			})
+		} finally {
+			setPendingAction(null)
+		}
+	}
+
+	const allowedActions = isReadOnlyUser() ? [] : (details?.AllowedActions ?? [])
+
 	const statusLabel = ServiceStatusLabels[service.state as ServiceStatus] ?? ""
 	const subStateLabel = ServiceSubStateLabels[service.sub as ServiceSubState] ?? ""
 
@@ -438,8 +507,54 @@ function SystemdSheet({
 	
 	const capitalize = (str: string) => `${str.charAt(0).toUpperCase()}${str.slice(1).toLowerCase()}`
 
+	const joinUnits = (...lists: (string[] | undefined)[]) => lists.flatMap((list) => list ?? []).join(", ")
+	const requiredBy = joinUnits(confirmation?.impact.RequiredBy, confirmation?.impact.BoundBy)
+	const wantedBy = joinUnits(confirmation?.impact.WantedBy)
+
 	return (
 		<Sheet open={sheetOpen} onOpenChange={setSheetOpen}>
+			<AlertDialog open={confirmation !== null} onOpenChange={(open) => !open && setConfirmation(null)}>
+				<AlertDialogContent>
+					<AlertDialogHeader>
+						<AlertDialogTitle>
+							<Trans>Are you sure?</Trans>
+						</AlertDialogTitle>
+						<AlertDialogDescription asChild>
+							<div className="grid gap-2">
+								<p>
+									{confirmation && actionLabels[confirmation.action]}: <span className="font-medium">{service.name}</span>
+								</p>
+								{requiredBy && (
+									<p>
+										{t`Required by`}: {requiredBy}
+									</p>
+								)}
+								{wantedBy && (
+									<p>
+										{t`Wanted by`}: {wantedBy}
+									</p>
+								)}
+								{confirmation?.impact.TasksCurrent != null && (
+									<p>
+										{t`Tasks`}: {confirmation.impact.TasksCurrent}
+									</p>
+								)}
+							</div>
+						</AlertDialogDescription>
+					</AlertDialogHeader>
+					<AlertDialogFooter>
+						<AlertDialogCancel>
+							<Trans>Cancel</Trans>
+						</AlertDialogCancel>
+						<AlertDialogAction
+							className={cn(buttonVariants({ variant: "destructive" }))}
+							onClick={() => confirmation && runAction(confirmation.action, confirmation.token)}
+						>
+							<Trans>Continue</Trans>
+						</AlertDialogAction>
+					</AlertDialogFooter>
+				</AlertDialogContent>
+			</AlertDialog>
 			<SheetContent className="w-full sm:max-w-220 p-6 overflow-y-auto">
 				<SheetHeader className="p-0">
 					<SheetTitle>
@@ -462,6 +577,23 @@ function SystemdSheet({
 						</Alert>
 					)}
 
+					{allowedActions.length > 0 && (
+						<div className="flex flex-wrap gap-2">
+							{allowedActions.map((action) => (
+								<Button
+									key={action}
+									variant="outline"
+									size="sm"
+									disabled={pendingAction !== null}
+									onClick={() => runAction(action)}
+								>
+									{pendingAction === action && <LoaderCircleIcon className="me-2 size-4 animate-spin" />}
+									{actionLabels[action] ?? action}
+								</Button>
+							))}
+						</div>
+					)}
+
 					<div>
 						<div className="border rounded-md">
 							<table className="w-full text-sm">
@@ -612,6 +744,17 @@ function SystemdSheet({
 							</table>
 						</div>
 					</div>
+
+					{details?.Logs && (
+						<div>
+							<h3 className="text-sm font-medium mb-3">
+								<Trans>Logs</Trans>
+							</h3>
+							<pre className="border rounded-md p-3 text-xs font-mono whitespace-pre-wrap break-all max-h-100 overflow-auto bg-muted/40">
+								{details.Logs}
+							</pre>
+						</div>
+					)}
 				</div>
 			</SheetContent>
 		</Sheet>
@@ -432,6 +432,8 @@ export interface SystemdServiceDetails {
 	ActiveState: string;
 	After: string[];
 	AllowIsolate: boolean;
+	/** Actions allowed by the agent's SERVICE_ACTIONS (added by agent) */
+	AllowedActions?: string[];
 	AssertResult: boolean;
 	AssertTimestamp: number;
 	AssertTimestampMonotonic: number;
@@ -481,6 +483,8 @@ export interface SystemdServiceDetails {
 	JoinsNamespaceOf: any[];
 	LoadError: string[];
 	LoadState: string;
+	/** Recent journal lines (added by agent) */
+	Logs?: string;
 	MainPID: number;
 	Markers: any[];
 	MemoryCurrent: number;